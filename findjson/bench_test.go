@@ -0,0 +1,269 @@
+package findjson
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// Corpora for benchmarks, generated deterministically, without math/rand, so results are
+// comparable across releases.
+
+// Large minified object with members of all kinds.
+func makeMinifiedObject(size int) []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, size+256))
+	buf.WriteString(`{"items":[`)
+	for n := 0; buf.Len() < size; n++ {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+
+		fmt.Fprintf(buf, `{"id":%d,"name":"item-%d","price":%d.%02d,"ratio":-%de-%d,`+
+			`"tags":["a%d","b%d"],"active":%t,"parent":null}`,
+			n, n, n*7%1000, n%100, n%9+1, n%5, n%13, n%17, n%2 == 0)
+	}
+
+	buf.WriteString(`]}`)
+	return buf.Bytes()
+}
+
+// Arrays nested in depth, each level holds a number.
+func makeDeepArray(depth int) []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, depth*8))
+	for n := 0; n < depth; n++ {
+		buf.WriteString(`[` + strconv.Itoa(n) + `,`)
+	}
+
+	buf.WriteString(`0`)
+	for n := 0; n < depth; n++ {
+		buf.WriteString(`]`)
+	}
+
+	return buf.Bytes()
+}
+
+// Pieces of string content with escapes and multi-byte UTF-8 chars.
+var benchmarkStringPieces = []string{
+	`lorem ipsum dolor sit amet, `,
+	`\"quoted\" \\ backslash \/ slash, `,
+	`tab\tnew line\n, `,
+	`中文 and 中文, `,
+	`emoji 😀 😀, `,
+}
+
+// One long string.
+func makeLongString(size int) []byte {
+	pieces := benchmarkStringPieces
+	buf := bytes.NewBuffer(make([]byte, 0, size+256))
+	buf.WriteByte('"')
+	for n := 0; buf.Len() < size; n++ {
+		buf.WriteString(pieces[n%len(pieces)])
+	}
+
+	buf.WriteByte('"')
+	return buf.Bytes()
+}
+
+// Array of long strings.
+func makeStringArray(size int) []byte {
+	pieces := benchmarkStringPieces
+	buf := bytes.NewBuffer(make([]byte, 0, size+256))
+	buf.WriteString(`[`)
+	for n := 0; buf.Len() < size; n++ {
+		if n > 0 {
+			buf.WriteString(`, `)
+		}
+
+		buf.WriteByte('"')
+		for k := 0; k < 16; k++ {
+			buf.WriteString(pieces[(n+k)%len(pieces)])
+		}
+
+		buf.WriteByte('"')
+	}
+
+	buf.WriteString(`]`)
+	return buf.Bytes()
+}
+
+// Lines of small objects, as NDJSON logs.
+func makeNdjson(size int) []byte {
+	buf := bytes.NewBuffer(make([]byte, 0, size+256))
+	for n := 0; buf.Len() < size; n++ {
+		fmt.Fprintf(buf, `{"ts":%d,"level":"info","msg":"request %d done","ms":%d.%d}`+"\n",
+			1666000000+n, n, n%300, n%10)
+	}
+
+	return buf.Bytes()
+}
+
+// Sparse JSON in large HTML page, the typical case for skipping.
+func makeSparseHtml(size int) []byte {
+	filler := []byte("<div class=\"row\"><span>lorem ipsum dolor sit amet</span></div>\n")
+	s := make([]byte, 0, size+64)
+	for len(s) < size {
+		s = append(s, filler...)
+	}
+
+	s = append(s, `<script>var data = {"a": [1, 2, 3]};</script>`...)
+	return s
+}
+
+type benchmarkCorpus struct {
+	name string
+	data []byte
+}
+
+func benchmarkCorpora() []benchmarkCorpus {
+	corpora := []benchmarkCorpus{
+		{"MinifiedObject", makeMinifiedObject(1 << 20)},
+		{"DeepArray", makeDeepArray(10000)},
+		{"StringHeavy", makeStringArray(1 << 20)},
+		{"SparseHtml", makeSparseHtml(1 << 20)},
+		{"Ndjson", makeNdjson(1 << 20)},
+	}
+
+	return corpora
+}
+
+var benchmarkStyles = []struct {
+	name  string
+	style Style
+}{
+	{"Normative", NormativeStyle},
+	{"JavaScript", JavaScriptLiteralStyle},
+}
+
+// Find all values of kind in s, as callers iterate on mixed content.
+func findAllWithStyle(s []byte, kind JsonValueKind, style Style) int {
+	count := 0
+	i := 0
+	for i < len(s) {
+		_, end, err := FindJsonWithOptions(s, i, kind, &Options{Style: style})
+		if err == nil {
+			count++
+		}
+
+		i = end
+	}
+
+	return count
+}
+
+func BenchmarkFindJsonWithStyle(b *testing.B) {
+	for _, corpus := range benchmarkCorpora() {
+		for _, style := range benchmarkStyles {
+			s := corpus.data
+			b.Run(corpus.name+"/"+style.name, func(b *testing.B) {
+				b.SetBytes(int64(len(s)))
+				for n := 0; n < b.N; n++ {
+					findAllWithStyle(s, JsonValueArray|JsonValueObject, style.style)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkScanners(b *testing.B) {
+	type scannerCase struct {
+		name    string
+		data    []byte
+		scanner func(s []byte, i int, style Style) (int, int, error)
+	}
+
+	caseList := []scannerCase{
+		{"Object", makeMinifiedObject(1 << 20), scanJsonObject},
+		{"Array", makeDeepArray(10000), scanJsonArray},
+		{"String", makeLongString(1 << 20), scanJsonStringWithStyle},
+	}
+
+	for _, c := range caseList {
+		for _, style := range benchmarkStyles {
+			c := c
+			style := style
+			b.Run(c.name+"/"+style.name, func(b *testing.B) {
+				b.SetBytes(int64(len(c.data)))
+				for n := 0; n < b.N; n++ {
+					if _, _, err := c.scanner(c.data, 0, style.style); err != nil {
+						b.Fatalf("scan %s failed: %s", c.name, err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// Find as FindJsonWithStyle did before skipping, getting scanner with kind.GetScanner on each
+// byte, as the baseline of benchmarks.
+func findJsonEachByte(s []byte, i int, kind JsonValueKind, style int) (int, int, error) {
+	l := len(s)
+	j := i
+	for j < l {
+		if isWhiteSpace(s[j]) {
+			j = jumpNextNonWhiteSpace(s, j)
+			if j >= l {
+				break
+			}
+		}
+
+		if scanner := kind.GetScanner(s[j], style); scanner != nil {
+			return scanner(s, j)
+		}
+
+		j++
+	}
+
+	return i, j, NewJsonError(j, "no JSON string found in %s", kind)
+}
+
+func benchmarkFind(b *testing.B, kind JsonValueKind, eachByte bool) {
+	s := makeSparseHtml(1 << 20)
+	sc := newJsonScanner(nil)
+	b.SetBytes(int64(len(s)))
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		i := 0
+		for i < len(s) {
+			var end int
+			if eachByte {
+				_, end, _ = findJsonEachByte(s, i, kind, NormativeStyle)
+			} else {
+				_, end, _ = sc.findSkipping(s, i, kind)
+			}
+
+			i = end
+		}
+	}
+}
+
+func BenchmarkFindObjectEachByte(b *testing.B) {
+	benchmarkFind(b, JsonValueObject, true)
+}
+
+func BenchmarkFindObjectSkipping(b *testing.B) {
+	benchmarkFind(b, JsonValueObject, false)
+}
+
+func BenchmarkFindAllEachByte(b *testing.B) {
+	benchmarkFind(b, JsonValueAll, true)
+}
+
+func BenchmarkFindAllSkipping(b *testing.B) {
+	benchmarkFind(b, JsonValueAll, false)
+}
+
+func TestBenchmarkCorpora(t *testing.T) {
+	for _, corpus := range benchmarkCorpora() {
+		for _, style := range benchmarkStyles {
+			if n := findAllWithStyle(corpus.data, JsonValueArray|JsonValueObject, style.style); n == 0 {
+				t.Errorf("no value found in corpus %s with %s style", corpus.name, style.name)
+			}
+		}
+	}
+
+	if !bytes.Equal(makeNdjson(4096), makeNdjson(4096)) {
+		t.Errorf("corpus is not deterministic")
+	}
+}
@@ -6,11 +6,14 @@ const (
 	jsonCharsetDigits        = 0x04
 	jsonCharsetDigitsNonZero = 0x08
 	jsonCharsetHexDigits     = 0x10
+	jsonCharsetControlChars  = 0x20
 )
 
 const (
 	// abbreviations
 	jWSP = jsonCharsetWhiteSpace
+	jCTL = jsonCharsetControlChars
+	jWSC = jsonCharsetWhiteSpace | jsonCharsetControlChars
 	jDGT = jsonCharsetDigits | jsonCharsetDigitsNonZero | jsonCharsetHexDigits
 	jHEX = jsonCharsetHexDigits
 	jESC = jsonCharsetEscapeChars
@@ -21,13 +24,13 @@ const (
 
 var charmap = [256]byte{
 	// 0     1     2     3     4     5     6     7
-	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // 0x00
+	jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, // 0x00
 	// 8     9     A     B     C     D     E     F
-	0x00, jWSP, jWSP, 0x00, 0x00, jWSP, 0x00, 0x00, // 0x08
+	jCTL, jWSC, jWSC, jCTL, jCTL, jWSC, jCTL, jCTL, // 0x08
 	// 0	 1     2     3     4     5     6     7
-	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // 0x10
+	jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, // 0x10
 	// 8     9     A     B     C     D     E     F
-	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // 0x18
+	jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, jCTL, // 0x18
 	// 0     1     2     3     4     5     6     7
 	jWSP, 0x00, jESC, 0x00, 0x00, 0x00, 0x00, 0x00, // 0x20
 	// 8     9     A     B     C     D     E     F
@@ -92,3 +95,56 @@ func isHexDigit(c byte) bool {
 func isNonZeroDigit(c byte) bool {
 	return charmap[c]&jsonCharsetDigitsNonZero != 0
 }
+
+func isControlChar(c byte) bool {
+	return charmap[c]&jsonCharsetControlChars != 0
+}
+
+func isIdentifierStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == '$'
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || isDigit(c)
+}
+
+// Get value of hex digit c, c MUST be a hex digit.
+func hexDigitValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+
+	case c >= 'A':
+		return c - 'A' + 10
+	}
+
+	return c - '0'
+}
+
+// Set of bytes as a bitmap, for searching any of many chars in one pass.
+type charSet [8]uint32
+
+func newCharSet(chars string) *charSet {
+	var set charSet
+	for k := 0; k < len(chars); k++ {
+		c := chars[k]
+		set[c>>5] |= 1 << (c & 31)
+	}
+
+	return &set
+}
+
+func (set *charSet) contains(c byte) bool {
+	return set[c>>5]&(1<<(c&31)) != 0
+}
+
+// Get index of the first byte of s in set, -1 if none.
+func indexCharSet(s []byte, set *charSet) int {
+	for k, c := range s {
+		if set.contains(c) {
+			return k
+		}
+	}
+
+	return -1
+}
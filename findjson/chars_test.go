@@ -61,3 +61,28 @@ func TestIsHexDigits(t *testing.T) {
 
 	testIsXXXInCharSet(t, "hex digit", set, isHexDigit)
 }
+
+func TestIsControlChar(t *testing.T) {
+	set := make([]byte, 0x20)
+	for i := range set {
+		set[i] = byte(i)
+	}
+
+	testIsXXXInCharSet(t, "control char", set, isControlChar)
+}
+
+func TestHexDigitValue(t *testing.T) {
+	set := "0123456789abcdef"
+	for i, c := range []byte(set) {
+		if v := hexDigitValue(c); v != byte(i) {
+			t.Errorf("hexDigitValue('%c') returns %d", c, v)
+		}
+	}
+
+	set = "0123456789ABCDEF"
+	for i, c := range []byte(set) {
+		if v := hexDigitValue(c); v != byte(i) {
+			t.Errorf("hexDigitValue('%c') returns %d", c, v)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package findjson
+
+// Weights of heuristics in confidence score, sum to 1.
+const (
+	confidenceLength    = 0.15 // bytes of value, full at confidenceFullLength
+	confidenceItems     = 0.2  // members or elements, full at confidenceFullItems
+	confidenceKeys      = 0.2  // keys are double-quoted strings
+	confidenceBounded   = 0.1  // bounded by white spaces or ends of buffer
+	confidenceBalanced  = 0.15 // not a fragment of a larger broken value
+	confidenceNormative = 0.2  // accepted in NormativeStyle
+
+	confidenceFullLength = 64
+	confidenceFullItems  = 4
+)
+
+// Check whether the value at s[i] is an object with a double-quoted key, or an array of such
+// objects, checked on the first member or element.
+func hasStringKeys(s []byte, i int) bool {
+	for i < len(s) && s[i] == jsonLBracket {
+		i = jumpNextNonWhiteSpace(s, i+1)
+	}
+
+	if i >= len(s) || s[i] != jsonLBrace {
+		return false
+	}
+
+	j := jumpNextNonWhiteSpace(s, i+1)
+	return j < len(s) && s[j] == jsonQuote
+}
+
+// Check whether value s[start:end] looks like a fragment of a larger value, which is next to
+// separators or brackets, e.g. {"a": 1} in `[0, {"a": 1} oops` and `{"a": 1} ]`.
+func isFragment(s []byte, start int, end int) bool {
+	j := start - 1
+	for j >= 0 && isWhiteSpace(s[j]) {
+		j--
+	}
+
+	if j >= 0 {
+		switch s[j] {
+		case jsonLBracket, jsonLBrace, jsonLParen, jsonComma, jsonColon:
+			return true
+		}
+	}
+
+	// comma after value is not checked, it is common in prose
+	if j = jumpNextNonWhiteSpace(s, end); j < len(s) {
+		switch s[j] {
+		case jsonRBracket, jsonRBrace, jsonRParen, jsonColon:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check whether value s[start:end] is bounded by white spaces, line ends or ends of buffer.
+func isBounded(s []byte, start int, end int) bool {
+	before := start == 0 || isWhiteSpace(s[start-1])
+	after := end >= len(s) || isWhiteSpace(s[end])
+	return before && after
+}
+
+// Get heuristic confidence score in [0, 1] that the value found is really JSON, rather than
+// something in noisy text looks like it, e.g. 1 and [] in prose. 0 on failure.
+//
+// Larger values with more members or elements score higher, so do objects with double-quoted
+// keys, values bounded by white spaces or line ends, values not next to separators or
+// brackets, which are fragments of larger broken values, and values accepted in NormativeStyle.
+func (m Match) Confidence() float64 {
+	if !m.Found() {
+		return 0
+	}
+
+	s := m.buffer
+	score := 0.0
+	if n := m.End - m.Start; n >= confidenceFullLength {
+		score += confidenceLength
+
+	} else {
+		score += confidenceLength * float64(n) / confidenceFullLength
+	}
+
+	if m.Items >= confidenceFullItems {
+		score += confidenceItems
+
+	} else {
+		score += confidenceItems * float64(m.Items) / confidenceFullItems
+	}
+
+	if hasStringKeys(s, m.Start) {
+		score += confidenceKeys
+	}
+
+	if isBounded(s, m.Start, m.End) {
+		score += confidenceBounded
+	}
+
+	if !isFragment(s, m.Start, m.End) {
+		score += confidenceBalanced
+	}
+
+	normative := m.Style == NormativeStyle
+	if !normative {
+		_, end, err := newJsonScannerWithStyle(NormativeStyle).scanValue(m.Bytes(), 0, JsonValueAll)
+		normative = err == nil && end == m.End-m.Start
+	}
+
+	if normative {
+		score += confidenceNormative
+	}
+
+	return score
+}
@@ -0,0 +1,57 @@
+package findjson
+
+import (
+	"testing"
+)
+
+func TestMatchConfidence(t *testing.T) {
+	type confidenceCase struct {
+		s     string
+		style Style
+		kind  JsonValueKind
+		exp   float64
+	}
+
+	caseList := []confidenceCase{
+		// 2 bytes, bounded, not fragment, normative
+		{`id 42 x`, NormativeStyle, JsonValueAll, 0.15*2/64 + 0.1 + 0.15 + 0.2},
+		// 8 bytes, 1 member, string keys, not bounded, normative
+		{`x={"a": 1};`, NormativeStyle, JsonValueAll, 0.15*8/64 + 0.2*1/4 + 0.2 + 0.15 + 0.2},
+		// 64 bytes, 4 elements, keys of objects in array, all
+		{"[{\"a\": 1}, {\"a\": 2}, {\"a\": 3}, {\"a\": 4}, {\"a\": 5}, {\"a\": 6}, {\"a\": 70}]\n",
+			NormativeStyle, JsonValueAll, 1},
+		// not accepted in NormativeStyle
+		{`{a: 1}`, JavaScriptLiteralStyle, JsonValueAll, 0.15*6/64 + 0.2*1/4 + 0.1 + 0.15},
+		// accepted in NormativeStyle, though found in JavaScriptLiteralStyle
+		{`{"a": 1}`, JavaScriptLiteralStyle, JsonValueAll, 0.15*8/64 + 0.2*1/4 + 0.2 + 0.1 + 0.15 + 0.2},
+		// fragments
+		{`"a", [1] oops`, NormativeStyle, JsonValueArray, 0.15*3/64 + 0.2*1/4 + 0.1 + 0.2},
+		{`{"a": [1, 2]} ]`, NormativeStyle, JsonValueObject, 0.15*13/64 + 0.2*1/4 + 0.2 + 0.1 + 0.2},
+	}
+
+	for _, c := range caseList {
+		m := FindJsonMatchWithOptions([]byte(c.s), 0, c.kind, &Options{Style: c.style})
+		if d := m.Confidence() - c.exp; !m.Found() || d > 1e-9 || d < -1e-9 {
+			t.Errorf("confidence of %s in %s is %f, expected %f", m.Bytes(), c.s, m.Confidence(), c.exp)
+		}
+	}
+
+	m := FindJsonMatch([]byte(`[1, 2`), 0, JsonValueAll)
+	if m.Confidence() != 0 {
+		t.Errorf("confidence of failure is %f", m.Confidence())
+	}
+}
+
+func TestFinderMinConfidence(t *testing.T) {
+	s := []byte(`Step 1 of "a" is [] and {"x": [1, 2, 3]}, then [{"y": 1}, {"z": 2}] and {"k": "v"}.`)
+	f := NewFinder(s, JsonValueAll, &Options{MinConfidence: 0.6})
+	checkFinderResult(t, finderCollect(f), []string{`{"x": [1, 2, 3]}`, `[{"y": 1}, {"z": 2}]`, `{"k": "v"}`})
+
+	f = NewFinder(s, JsonValueAll, &Options{MinConfidence: 0.7})
+	checkFinderResult(t, finderCollect(f), []string{`[{"y": 1}, {"z": 2}]`})
+
+	m := FindJsonMatchWithOptions(s, 0, JsonValueAll, &Options{MinConfidence: 0.7, Selection: SelectLargest})
+	if string(m.Bytes()) != `[{"y": 1}, {"z": 2}]` {
+		t.Errorf("FindJsonMatchWithOptions() returns %+v", m)
+	}
+}
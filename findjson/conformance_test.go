@@ -0,0 +1,136 @@
+package findjson
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const conformanceDir = "testdata/JSONTestSuite/test_parsing"
+
+// Policy of implementation defined cases, see testdata/JSONTestSuite/README.md. Each entry is
+// whether the file is accepted with default options, and with ValidateUTF8 and
+// RejectLoneSurrogates both enabled.
+type conformancePolicy struct {
+	lenient bool
+	strict  bool
+}
+
+var conformancePolicies = map[string]conformancePolicy{
+	// numbers are checked for syntax only, range is up to the caller, see Number.
+	"i_number_double_huge_neg_exp.json":   {true, true},
+	"i_number_huge_exp.json":              {true, true},
+	"i_number_neg_int_huge_exp.json":      {true, true},
+	"i_number_pos_double_huge_exp.json":   {true, true},
+	"i_number_real_neg_overflow.json":     {true, true},
+	"i_number_real_pos_overflow.json":     {true, true},
+	"i_number_real_underflow.json":        {true, true},
+	"i_number_too_big_neg_int.json":       {true, true},
+	"i_number_too_big_pos_int.json":       {true, true},
+	"i_number_very_big_negative_int.json": {true, true},
+
+	// escaped lone surrogates are rejected with RejectLoneSurrogates only.
+	"i_object_key_lone_2nd_surrogate.json":                {true, false},
+	"i_string_1st_surrogate_but_2nd_missing.json":         {true, false},
+	"i_string_1st_valid_surrogate_2nd_invalid.json":       {true, false},
+	"i_string_incomplete_surrogate_and_escape_valid.json": {true, false},
+	"i_string_incomplete_surrogates_escape_valid.json":    {true, false},
+	"i_string_invalid_lonely_surrogate.json":              {true, false},
+	"i_string_invalid_surrogate.json":                     {true, false},
+	"i_string_inverted_surrogates_U+1D11E.json":           {true, false},
+	"i_string_lone_second_surrogate.json":                 {true, false},
+
+	// invalid UTF-8 in strings is rejected with ValidateUTF8 only.
+	"i_string_invalid_utf-8.json":               {true, false},
+	"i_string_iso_latin_1.json":                 {true, false},
+	"i_string_lone_utf8_continuation_byte.json": {true, false},
+	"i_string_not_in_unicode_range.json":        {true, false},
+	"i_string_overlong_sequence_2_bytes.json":   {true, false},
+	"i_string_truncated-utf-8.json":             {true, false},
+	"i_string_UTF8_surrogate_U+D800.json":       {true, false},
+
+	// input is UTF-8 without BOM, a BOM is not white space.
+	"i_string_UTF-16LE_with_BOM.json":         {false, false},
+	"i_string_utf16BE_no_BOM.json":            {false, false},
+	"i_structure_UTF-8_BOM_empty_object.json": {false, false},
+
+	// nesting is limited by MaxDepth only.
+	"i_structure_500_nested_arrays.json": {true, true},
+}
+
+// Check whether the first value found in s spans s, except leading and trailing white spaces.
+func acceptsWholeInput(s []byte, options *Options) bool {
+	start, end, err := FindJsonWithOptions(s, 0, JsonValueAll, options)
+	return spansWholeInput(s, start, end, err)
+}
+
+func spansWholeInput(s []byte, start int, end int, err error) bool {
+	if err != nil {
+		return false
+	}
+
+	return jumpNextNonWhiteSpace(s, 0) == start && jumpNextNonWhiteSpace(s, end) == len(s)
+}
+
+func TestConformance(t *testing.T) {
+	files, err := ioutil.ReadDir(conformanceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strict := &Options{ValidateUTF8: true, RejectLoneSurrogates: true}
+	count := 0
+	for _, file := range files {
+		name := file.Name()
+		if !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		s, err := ioutil.ReadFile(filepath.Join(conformanceDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		count++
+		lenient := acceptsWholeInput(s, nil)
+		start, end, err := FindJsonWithStyle(s, 0, JsonValueAll, NormativeStyle)
+		if v := spansWholeInput(s, start, end, err); v != lenient {
+			t.Errorf("%s: accepted %v by FindJsonWithStyle, %v by FindJsonWithOptions", name, v, lenient)
+		}
+
+		switch name[:2] {
+		case "y_":
+			if !lenient || !acceptsWholeInput(s, strict) {
+				t.Errorf("%s is rejected", name)
+			}
+
+		case "n_":
+			if lenient || acceptsWholeInput(s, strict) {
+				t.Errorf("%s is accepted", name)
+			}
+
+		case "i_":
+			policy, ok := conformancePolicies[name]
+			if !ok {
+				t.Errorf("%s has no policy", name)
+				continue
+			}
+
+			if lenient != policy.lenient {
+				t.Errorf("%s: accepted %v with default options, expected %v", name, lenient, policy.lenient)
+			}
+
+			if v := acceptsWholeInput(s, strict); v != policy.strict {
+				t.Errorf("%s: accepted %v with strict options, expected %v", name, v, policy.strict)
+			}
+
+		default:
+			t.Errorf("%s has unknown prefix", name)
+		}
+	}
+
+	if count == 0 {
+		t.Fatalf("no case found in %s", conformanceDir)
+	}
+}
@@ -2,8 +2,32 @@ package findjson
 
 import "fmt"
 
+// Error code of JsonError, tells which rule is violated.
+type JsonErrorCode int
+
+const (
+	JsonErrorSyntax        = JsonErrorCode(0) // generic grammar error
+	JsonErrorControlChar   = JsonErrorCode(1) // raw control char in string
+	JsonErrorInvalidUTF8   = JsonErrorCode(2) // invalid UTF-8 sequence in string
+	JsonErrorLoneSurrogate = JsonErrorCode(3) // escaped UTF-16 surrogate without its pair
+	JsonErrorDuplicateKey  = JsonErrorCode(4) // key appears more than once in an object
+
+	// limits violation, see Options
+	JsonErrorTooDeep         = JsonErrorCode(5)  // MaxDepth
+	JsonErrorValueTooLong    = JsonErrorCode(6)  // MaxValueLength
+	JsonErrorStringTooLong   = JsonErrorCode(7)  // MaxStringLength
+	JsonErrorNumberTooLong   = JsonErrorCode(8)  // MaxNumberLength
+	JsonErrorTooManyMembers  = JsonErrorCode(9)  // MaxMembers
+	JsonErrorTooManyElements = JsonErrorCode(10) // MaxElements
+
+	// number conversion, see Number
+	JsonErrorNotInteger       = JsonErrorCode(11) // number has fraction, converting to integer
+	JsonErrorNumberOutOfRange = JsonErrorCode(12) // number does not fit in target type
+)
+
 type JsonError struct {
 	Offset  int
+	Code    JsonErrorCode
 	Message string
 }
 
@@ -15,8 +39,15 @@ func (e *JsonError) Error() string {
 func NewJsonError(Offset int, message string, args ...interface{}) *JsonError {
 	e := &JsonError{
 		Offset:  Offset,
+		Code:    JsonErrorSyntax,
 		Message: fmt.Sprintf(message, args...),
 	}
 
 	return e
 }
+
+func NewJsonErrorWithCode(code JsonErrorCode, offset int, message string, args ...interface{}) *JsonError {
+	e := NewJsonError(offset, message, args...)
+	e.Code = code
+	return e
+}
@@ -10,3 +10,18 @@ func TestError(t *testing.T) {
 		t.Errorf("unexpected error message: %s", err.Error())
 	}
 }
+
+func TestErrorWithCode(t *testing.T) {
+	err := NewJsonErrorWithCode(JsonErrorControlChar, 42, "control char 0x%02x", 10)
+	if err.Error() != "JSON error at 42: control char 0x0a" {
+		t.Errorf("unexpected error message: %s", err.Error())
+	}
+
+	if err.Code != JsonErrorControlChar {
+		t.Errorf("unexpected error code: %d", err.Code)
+	}
+
+	if err := NewJsonError(0, "syntax"); err.Code != JsonErrorSyntax {
+		t.Errorf("unexpected error code: %d", err.Code)
+	}
+}
@@ -55,7 +55,7 @@ func ExampleFindJsonWithStyle_javascript_style() {
 
 	i := 0
 	for i < len(s) {
-		start, end, err := FindJsonWithStyle(s, i, JsonValueArray, JavaScriptStyle)
+		start, end, err := FindJsonWithStyle(s, i, JsonValueArray, JavaScriptLiteralStyle)
 		if err == nil {
 			fmt.Println(string(s[start:end]))
 		}
@@ -67,3 +67,41 @@ func ExampleFindJsonWithStyle_javascript_style() {
 	// [1, 2, 3]
 	// [4, 5, 6,]
 }
+
+func ExampleFindJsonWithOptions_provider() {
+	s := []byte(`<script>
+		const limits = {"size": 1_048_576, "count": [1_000, 2_000]};
+	</script>`)
+
+	// number scanner accepts underscores as digit separators
+	scanNumber := func(s []byte, i int) (int, int, error) {
+		j := i
+		for j < len(s) && ((s[j] >= '0' && s[j] <= '9') || (s[j] == '_' && j > i)) {
+			j++
+		}
+
+		if j <= i {
+			return i, i, NewJsonError(i, "expect digit")
+		}
+
+		return i, j, nil
+	}
+
+	options := &Options{
+		Provider: func(kind JsonValueKind) JsonTokenScanner {
+			if kind == JsonValueNumber {
+				return scanNumber
+			}
+
+			return nil
+		},
+	}
+
+	start, end, err := FindJsonWithOptions(s, 0, JsonValueObject, options)
+	if err == nil {
+		fmt.Println(string(s[start:end]))
+	}
+
+	// Output:
+	// {"size": 1_048_576, "count": [1_000, 2_000]}
+}
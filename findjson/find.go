@@ -1,23 +1,57 @@
 package findjson
 
-const (
-	NormativeStyle  = 0
-	JavaScriptStyle = 1
+import (
+	"bytes"
 )
 
-// Find JSON string in mixed content, start from offset i, with style specified.
-func FindJsonWithStyle(s []byte, i int, kind JsonValueKind, style int) (int, int, error) {
+// Find JSON string in mixed content, start from offset i, with options specified.
+func FindJsonWithOptions(s []byte, i int, kind JsonValueKind, options *Options) (int, int, error) {
+	sc := newJsonScanner(options)
+	if sc.options.Selection == SelectFirst && !sc.hasThresholds() {
+		return sc.find(s, i, kind)
+	}
+
+	m := sc.findSelected(s, i, kind)
+	if !m.Found() {
+		return m.Start, m.PartialEnd, m.Err
+	}
+
+	return m.Start, m.End, nil
+}
+
+func (sc *jsonScanner) find(s []byte, i int, kind JsonValueKind) (int, int, error) {
+	if !sc.options.Style.IsValid() {
+		return i, i, NewJsonError(i, "unknown style %d", sc.options.Style)
+	}
+
+	if sc.provided != nil {
+		// chars out of FIRST SET may be matched by provided scanners
+		return sc.findEachByte(s, i, kind)
+	}
+
+	return sc.findSkipping(s, i, kind)
+}
+
+// Find by checking each byte, which works with provided scanners.
+func (sc *jsonScanner) findEachByte(s []byte, i int, kind JsonValueKind) (int, int, error) {
 	l := len(s)
 	j := i
 	for j < l {
 		if isWhiteSpace(s[j]) {
 			j = jumpNextNonWhiteSpace(s, j)
+			if j >= l {
+				break
+			}
+		}
+
+		if kind&firstSetKind(s[j], sc.style) != 0 {
+			return sc.scanRootValue(s, j, kind)
 		}
 
-		c := s[j]
-		if scanner := kind.GetScanner(c, style); scanner != nil {
-			start, end, err := scanner(s, j)
-			return start, end, err
+		if sc.provided != nil {
+			if start, end, err := sc.scanRootValue(s, j, kind); end > start {
+				return start, end, err
+			}
 		}
 
 		j++
@@ -26,6 +60,36 @@ func FindJsonWithStyle(s []byte, i int, kind JsonValueKind, style int) (int, int
 	return i, j, NewJsonError(j, "no JSON string found in %s", kind)
 }
 
+// Find by jumping to the next char in FIRST SET of kind, with bytes.IndexByte for a single
+// char, e.g. '{' of objects, or a bitmap of chars, without checking grammar on each byte.
+func (sc *jsonScanner) findSkipping(s []byte, i int, kind JsonValueKind) (int, int, error) {
+	l := len(s)
+	if i >= l {
+		return i, i, NewJsonError(i, "no JSON string found in %s", kind)
+	}
+
+	chars := firstSetChars(kind, sc.style)
+	k := -1
+	if len(chars) == 1 {
+		k = bytes.IndexByte(s[i:], chars[0])
+
+	} else if len(chars) > 1 {
+		k = indexCharSet(s[i:], newCharSet(chars))
+	}
+
+	if k < 0 {
+		return i, l, NewJsonError(l, "no JSON string found in %s", kind)
+	}
+
+	return sc.scanRootValue(s, i+k, kind)
+}
+
+// Find JSON string in mixed content, start from offset i, with style specified, a preset or a
+// set of features, see Style.
+func FindJsonWithStyle(s []byte, i int, kind JsonValueKind, style int) (int, int, error) {
+	return FindJsonWithOptions(s, i, kind, &Options{Style: Style(style)})
+}
+
 // Find JSON string in mixed content, start from offset i.
 func FindJson(s []byte, i int, kind JsonValueKind) (int, int, error) {
 	return FindJsonWithStyle(s, i, kind, NormativeStyle)
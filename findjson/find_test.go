@@ -1,6 +1,7 @@
 package findjson
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -84,3 +85,218 @@ func TestFindJsonBooleanInTokenArray(t *testing.T) {
 		}
 	}
 }
+
+func TestFindJsonWithOptions(t *testing.T) {
+	//           0         1         2         3
+	//           0123456789012345678901234567890
+	s := []byte("{\"a\": \"\xff\"} {\"b\": \"ok\"}")
+
+	options := &Options{
+		ValidateUTF8: true,
+	}
+
+	start, end, err := FindJsonWithOptions(s, 0, JsonValueObject, options)
+	if err == nil {
+		t.Fatalf("FindJsonWithOptions(s, 0) returns %d, %d, nil", start, end)
+	}
+
+	if code := err.(*JsonError).Code; code != JsonErrorInvalidUTF8 || end != 7 {
+		t.Errorf("FindJsonWithOptions(s, 0) returns %d, %d, %s", start, end, err)
+	}
+
+	start, end, err = FindJsonWithOptions(s, end, JsonValueObject, options)
+	if err != nil || string(s[start:end]) != `{"b": "ok"}` {
+		t.Errorf("FindJsonWithOptions(s, 7) returns %d, %d, %v", start, end, err)
+	}
+
+	start, end, err = FindJsonWithOptions(s, 0, JsonValueObject, nil)
+	if err != nil || start != 0 || end != 10 {
+		t.Errorf("FindJsonWithOptions(s, 0, nil) returns %d, %d, %v", start, end, err)
+	}
+
+	if _, _, err := FindJsonWithStyle(s, 0, JsonValueObject, -1); err == nil {
+		t.Errorf("FindJsonWithStyle(s, 0, -1) returns nil error")
+	}
+}
+
+func TestFindJsonWithLegacyStyle(t *testing.T) {
+	// int styles of callers before Style, JavaScriptStyle is trailing commas and control chars
+	s := []byte(`x [1, 2,]`)
+	for _, style := range []int{1, JavaScriptStyle} {
+		start, end, err := FindJsonWithStyle(s, 0, JsonValueArray, style)
+		if err != nil || start != 2 || end != len(s) {
+			t.Errorf("FindJsonWithStyle(s, 0, %d) returns %d, %d, %v", style, start, end, err)
+		}
+
+		if f := JsonValueAll.GetScanner('[', style); f == nil {
+			t.Errorf("GetScanner('[', %d) returns nil", style)
+		}
+
+		if _, end, err := GetScannerOf(JsonValueArray, style)(s, 2); err != nil || end != len(s) {
+			t.Errorf("GetScannerOf(JsonValueArray, %d) returns %d, %v", style, end, err)
+		}
+	}
+
+	s = []byte(`x ['a', 1,]`)
+	if _, _, err := FindJsonWithStyle(s, 0, JsonValueArray, JavaScriptStyle); err == nil {
+		t.Errorf("FindJsonWithStyle(s, 0, JavaScriptStyle) accepts single quotes")
+	}
+
+	start, end, err := FindJsonWithStyle(s, 0, JsonValueArray, JavaScriptLiteralStyle)
+	if err != nil || start != 2 || end != len(s) {
+		t.Errorf("FindJsonWithStyle(s, 0, JavaScriptLiteralStyle) returns %d, %d, %v", start, end, err)
+	}
+
+	if _, _, err := FindJsonWithStyle(s, 0, JsonValueArray, NormativeStyle); err == nil {
+		t.Errorf("FindJsonWithStyle(s, 0, NormativeStyle) returns nil error")
+	}
+}
+
+func TestFindJsonTrailingWhiteSpace(t *testing.T) {
+	s := []byte("no json here   ")
+
+	start, end, err := FindJson(s, 0, JsonValueObject)
+	if err == nil {
+		t.Fatalf("FindJson(s, 0) returns %d, %d, nil", start, end)
+	}
+
+	if err.Error() != "JSON error at 15: no JSON string found in object" {
+		t.Errorf("FindJson(s, 0) returns %d, %d, %s", start, end, err)
+	}
+}
+
+func TestFindJsonWithLimits(t *testing.T) {
+	type limitCase struct {
+		s       string
+		options Options
+		code    JsonErrorCode
+		offset  int
+	}
+
+	caseList := []limitCase{
+		{`[[1], [[2]]]`, Options{MaxDepth: 2}, JsonErrorTooDeep, 7},
+		{`{"a": {"b": {}}}`, Options{MaxDepth: 2}, JsonErrorTooDeep, 12},
+		{`[1, 2, 3, 4]`, Options{MaxValueLength: 8}, JsonErrorValueTooLong, 8},
+		{`12345678`, Options{MaxValueLength: 4}, JsonErrorValueTooLong, 4},
+		{`"abcdefgh"`, Options{MaxValueLength: 9}, JsonErrorValueTooLong, 9},
+		{`["abc", "abcdef"]`, Options{MaxStringLength: 4}, JsonErrorStringTooLong, 13},
+		{`{"abcdef": 1}`, Options{MaxStringLength: 4}, JsonErrorStringTooLong, 6},
+		{`"a\u0041"`, Options{MaxStringLength: 2}, JsonErrorStringTooLong, 3},
+		{`"\u0041"`, Options{MaxStringLength: 2}, JsonErrorStringTooLong, 3},
+		{`"\u0041\u0042cd"`, Options{MaxStringLength: 2}, JsonErrorStringTooLong, 3},
+		{`"ab\n"`, Options{MaxStringLength: 2}, JsonErrorStringTooLong, 3},
+		{`[1, 12345678]`, Options{MaxNumberLength: 4}, JsonErrorNumberTooLong, 8},
+		{`{"a": 1, "b": 2, "c": 3}`, Options{MaxMembers: 2}, JsonErrorTooManyMembers, 17},
+		{`[1, 2, 3]`, Options{MaxElements: 2}, JsonErrorTooManyElements, 7},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		start, end, err := FindJsonWithOptions(s, 0, JsonValueAll, &c.options)
+		if err == nil {
+			t.Errorf("FindJsonWithOptions(%s) returns %d, %d, nil", c.s, start, end)
+			continue
+		}
+
+		e := err.(*JsonError)
+		if e.Code != c.code || e.Offset != c.offset || end != c.offset {
+			t.Errorf("FindJsonWithOptions(%s) returns %d, %d, %s (code %d)", c.s, start, end, err, e.Code)
+		}
+	}
+
+	successList := []limitCase{
+		{`[[1], [2]]`, Options{MaxDepth: 2}, 0, 0},
+		{`[1, 2, 3]`, Options{MaxValueLength: 9}, 0, 0},
+		{`1234`, Options{MaxValueLength: 4}, 0, 0},
+		{`["abcd"]`, Options{MaxStringLength: 4}, 0, 0},
+		{`["a\n"]`, Options{MaxStringLength: 3}, 0, 0},
+		{`["\u0041"]`, Options{MaxStringLength: 6}, 0, 0},
+		{`["䶮"]`, Options{MaxStringLength: 6}, 0, 0},
+		{`1234`, Options{MaxNumberLength: 4}, 0, 0},
+		{`{"a": 1, "b": 2}`, Options{MaxMembers: 2}, 0, 0},
+		{`[1, 2]`, Options{MaxElements: 2}, 0, 0},
+		{`[1, 2,]`, Options{Style: JavaScriptLiteralStyle, MaxElements: 2}, 0, 0},
+	}
+
+	for _, c := range successList {
+		s := []byte(c.s)
+		start, end, err := FindJsonWithOptions(s, 0, JsonValueAll, &c.options)
+		if err != nil || start != 0 || end != len(s) {
+			t.Errorf("FindJsonWithOptions(%s) returns %d, %d, %v", c.s, start, end, err)
+		}
+	}
+
+	{
+		// error within the value length limit is reported as is.
+		s := []byte(`[1, x, 3, 4, 5, 6]`)
+		options := &Options{MaxValueLength: 8}
+		start, end, err := FindJsonWithOptions(s, 0, JsonValueArray, options)
+		if err == nil || err.(*JsonError).Code != JsonErrorSyntax || end != 4 {
+			t.Errorf("FindJsonWithOptions(%s) returns %d, %d, %v", s, start, end, err)
+		}
+	}
+}
+
+func TestFindJsonPythonStyle(t *testing.T) {
+	s := []byte(`2022-10-19 INFO params={'a': True, 'b': None, 'c': (1, 2)} Nothing Found`)
+
+	got := make([]string, 0)
+	i := 0
+	for i < len(s) {
+		start, end, err := FindJsonWithStyle(s, i, JsonValueObject|JsonValueNull, PythonStyle)
+		if err == nil {
+			got = append(got, string(s[start:end]))
+		}
+
+		i = end
+	}
+
+	exp := []string{
+		`{'a': True, 'b': None, 'c': (1, 2)}`,
+	}
+
+	if len(got) != len(exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	for i, v := range exp {
+		if got[i] != v {
+			t.Errorf("exp[%d](%s) != got[%d](%s)", i, v, i, got[i])
+		}
+	}
+}
+
+func TestFindJsonSkippingAgreesWithEachByte(t *testing.T) {
+	inputs := []string{
+		``,
+		`   `,
+		`no json here`,
+		`<p>a = [1, 2, 3]; b = {"c": "d"}; e = nul; f = true</p>`,
+		`{"a": [1, 2,], 'b': (3, 4), c: None, d: NaN, e: .5}`,
+		`/* comment */ [1, /* 2 */ 3] // 4`,
+		`x = {"a": [1, 2`,
+	}
+
+	styles := []Style{NormativeStyle, JavaScriptLiteralStyle, PythonStyle, styleAllFeatures}
+	kinds := []JsonValueKind{
+		JsonValueNull, JsonValueBoolean, JsonValueNumber, JsonValueString,
+		JsonValueArray, JsonValueObject, JsonValueArray | JsonValueObject, JsonValueAll,
+	}
+
+	for _, v := range inputs {
+		s := []byte(v)
+		for _, style := range styles {
+			for _, kind := range kinds {
+				sc := newJsonScannerWithStyle(style)
+				for i := 0; i <= len(s); i++ {
+					start1, end1, err1 := sc.findSkipping(s, i, kind)
+					start2, end2, err2 := sc.findEachByte(s, i, kind)
+					if start1 != start2 || end1 != end2 || fmt.Sprint(err1) != fmt.Sprint(err2) {
+						t.Errorf("find(%q, %d, %s, %s) differs: (%d, %d, %v) != (%d, %d, %v)",
+							v, i, kind, style, start1, end1, err1, start2, end2, err2)
+					}
+				}
+			}
+		}
+	}
+}
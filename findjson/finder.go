@@ -0,0 +1,328 @@
+package findjson
+
+// How searching resumes after a candidate fails.
+type SearchMode int
+
+const (
+	// Resume at the offset where the candidate fails, values nested in the failed part are
+	// skipped, e.g. {"a":1} in `{ not json {"a":1}`.
+	SearchResumeAtFailure = SearchMode(0)
+
+	// Resume at the byte after the start of the failed candidate, so nested values are still
+	// found. Results of arrays and objects are memoized, each one is scanned only once, keeping
+	// the whole search linear.
+	SearchResumeAfterStart = SearchMode(1)
+)
+
+// Bytes of lookahead a scanner may take beyond the failure offset, e.g. "Infinity".
+const memoLookahead = 16
+
+// Result of scanning an array or object at some offset.
+type memoResult struct {
+	end    int
+	err    error
+	height int // depth of nesting of the value
+	items  int // members or elements of the value
+}
+
+// Span of the last comment or failed string, [start, end).
+type memoSpan struct {
+	start  int
+	end    int
+	closed bool  // comment is closed
+	err    error // error of failed string
+}
+
+// Memoized results in one buffer, for scanning each byte in a bounded number of times.
+//
+// Arrays and objects are indexed by start offset. Comments and failed strings are different,
+// they are rarely scanned as values, but contain candidates inside them, e.g. `/* [ [ [`.
+// Only the last one of each is kept, since any comment or string starts inside it ends at the
+// same offset.
+type scanMemo struct {
+	length  int // length of the whole buffer
+	results map[int]memoResult
+
+	lastLineComment  memoSpan
+	lastBlockComment memoSpan
+	lastString       memoSpan // the last failed string
+	lastNumber       memoSpan // the last failed number
+}
+
+func newScanMemo(length int) *scanMemo {
+	none := memoSpan{start: -1, end: -1}
+	m := &scanMemo{
+		length:           length,
+		results:          make(map[int]memoResult),
+		lastLineComment:  none,
+		lastBlockComment: none,
+		lastString:       none,
+		lastNumber:       none,
+	}
+
+	return m
+}
+
+// Check whether result at end of s, which may be truncated by MaxValueLength, is the same as
+// on the whole buffer.
+func (m *scanMemo) isStable(s []byte, end int) bool {
+	return len(s) == m.length || end+memoLookahead < len(s)
+}
+
+// Remember result of value at i scanned on s, which may be truncated by MaxValueLength.
+// Results depends on depth, or on content beyond truncated s, are not remembered.
+func (m *scanMemo) remember(s []byte, i int, r memoResult) {
+	if r.err != nil {
+		if e, ok := r.err.(*JsonError); ok && e.Code == JsonErrorTooDeep {
+			return
+		}
+
+		if !m.isStable(s, r.end) {
+			return
+		}
+	}
+
+	m.results[i] = r
+}
+
+// Get end of comment at s[i], like commentEnd.
+func (m *scanMemo) commentEnd(s []byte, i int) int {
+	last := &m.lastBlockComment
+	if s[i+1] == jsonSlash {
+		last = &m.lastLineComment
+	}
+
+	// a comment starts inside the last one finds the same terminator, if its search starts
+	// before the terminator, for "*/" of block comments, 2 bytes before end.
+	inside := i > last.start && i < last.end
+	if last.closed && last == &m.lastBlockComment {
+		inside = inside && i+2 <= last.end-len(jsonBlockCommentEnd)
+	}
+
+	if inside {
+		if last.end > len(s) {
+			return len(s)
+		}
+
+		return last.end
+	}
+
+	end, closed := commentEnd(s, i)
+	if closed || len(s) == m.length {
+		*last = memoSpan{start: i, end: end, closed: closed}
+	}
+
+	return end
+}
+
+// Get result of string at s[i] from the last failed string. A string starts inside it with the
+// same quote starts at an escaped quote, from the next byte, both are scanned in the same way.
+func (m *scanMemo) findFailedString(s []byte, i int) (memoSpan, bool) {
+	last := m.lastString
+	found := i > last.start && i < last.end && s[i] == s[last.start] && m.isStable(s, last.end)
+	return last, found
+}
+
+// Remember failed string s[i:end], failures depend on length of string are not remembered.
+func (m *scanMemo) rememberString(s []byte, i int, end int, err error) {
+	if e, ok := err.(*JsonError); ok && e.Code == JsonErrorStringTooLong {
+		return
+	}
+
+	if m.isStable(s, end) {
+		m.lastString = memoSpan{start: i, end: end, err: err}
+	}
+}
+
+// Get result of number at s[i] from the last failed number. A number starts at a digit inside
+// it is scanned in the same state to the same failure, e.g. 23e of 123e, except a leading zero
+// followed by digits, which is a number itself.
+func (m *scanMemo) findFailedNumber(s []byte, i int) (memoSpan, bool) {
+	last := m.lastNumber
+	if i <= last.start || i >= last.end || !isDigit(s[i]) || !m.isStable(s, last.end) {
+		return last, false
+	}
+
+	leadingZero := s[i] == jsonDigitZero && i+1 < len(s) && isDigit(s[i+1])
+	return last, !leadingZero
+}
+
+// Remember failed number s[i:end].
+func (m *scanMemo) rememberNumber(s []byte, i int, end int, err error) {
+	if m.isStable(s, end) {
+		m.lastNumber = memoSpan{start: i, end: end, err: err}
+	}
+}
+
+// Scan array or object of kind k at s[i] with memoized results.
+func (sc *jsonScanner) scanMemoized(s []byte, i int, k JsonValueKind) (int, int, error) {
+	if r, ok := sc.memo.results[i]; ok && r.end <= len(s) {
+		if d := sc.depth + r.height; d > sc.maxDepth {
+			sc.maxDepth = d
+		}
+
+		sc.items = r.items
+		return i, r.end, r.err
+	}
+
+	outer := sc.maxDepth
+	sc.maxDepth = sc.depth
+	start, end, err := sc.scanContainer(s, i, k)
+	height := sc.maxDepth - sc.depth
+	if sc.maxDepth < outer {
+		sc.maxDepth = outer
+	}
+
+	sc.memo.remember(s, i, memoResult{end: end, err: err, height: height, items: sc.items})
+	return start, end, err
+}
+
+// Scan array or object of kind k at s[i], like scanValue.
+func (sc *jsonScanner) scanContainer(s []byte, i int, k JsonValueKind) (int, int, error) {
+	if sc.provided != nil {
+		if scanner := sc.provided[kindIndex(k)]; scanner != nil {
+			return sc.scanProvided(s, i, k, scanner)
+		}
+	}
+
+	err := sc.enterContainer(i)
+	if err != nil {
+		sc.leaveContainer()
+		return i, i, err
+	}
+
+	start, end := i, i
+	if k == JsonValueArray {
+		start, end, err = sc.scanArray(s, i)
+
+	} else {
+		start, end, err = sc.scanObject(s, i)
+	}
+
+	sc.leaveContainer()
+	return start, end, err
+}
+
+// Check whether thresholds are set in options.
+func (sc *jsonScanner) hasThresholds() bool {
+	o := sc.options
+	return o.MinValueLength > 0 || o.MinItems > 0 || o.MinDepth > 0 || o.MinConfidence > 0
+}
+
+// Check whether value found is below any threshold in options.
+func (sc *jsonScanner) isTrivial(m Match) bool {
+	o := sc.options
+	if m.End-m.Start < o.MinValueLength || m.Items < o.MinItems || m.Depth < o.MinDepth {
+		return true
+	}
+
+	return o.MinConfidence > 0 && m.Confidence() < o.MinConfidence
+}
+
+// Get offset where searching resumes after value m below thresholds. Arrays and objects are
+// searched inside, values nested in them may have more items or higher confidence, except when
+// m is too short or too shallow, which nested values are too. Results are memoized once values
+// are searched inside, so each nested one is scanned only once.
+func (sc *jsonScanner) skipTrivial(m Match) int {
+	o := sc.options
+	if m.Kind != JsonValueArray && m.Kind != JsonValueObject {
+		return m.End
+	}
+
+	if m.End-m.Start < o.MinValueLength || m.Depth < o.MinDepth {
+		return m.End
+	}
+
+	if sc.memo == nil {
+		sc.memo = newScanMemo(len(m.buffer))
+	}
+
+	return m.Start + 1
+}
+
+// Finder finds JSON values in a buffer one by one, failed candidates are skipped.
+//
+//	f := NewFinder(s, JsonValueObject, nil)
+//	for f.Next() {
+//		m := f.Match()
+//		...
+//	}
+type Finder struct {
+	sc     *jsonScanner
+	buffer []byte
+	kind   JsonValueKind
+	mode   SearchMode
+	offset int
+	match  Match
+}
+
+// Create finder of values of kind in s, with options, nil for default.
+func NewFinder(s []byte, kind JsonValueKind, options *Options) *Finder {
+	return newFinderAt(s, 0, kind, newJsonScanner(options))
+}
+
+// Create finder of values of kind in s from offset i with scanner sc, e.g. forked to share
+// warnings already reported.
+func newFinderAt(s []byte, i int, kind JsonValueKind, sc *jsonScanner) *Finder {
+	f := &Finder{
+		sc:     sc,
+		buffer: s,
+		kind:   kind,
+		offset: i,
+	}
+
+	return f
+}
+
+// Set how searching resumes after a candidate fails, MUST be called before Next.
+func (f *Finder) SetMode(mode SearchMode) {
+	f.mode = mode
+	if mode == SearchResumeAfterStart {
+		f.sc.memo = newScanMemo(len(f.buffer))
+
+	} else {
+		f.sc.memo = nil
+	}
+}
+
+// Set offset where the next call of Next starts searching, offsets of values are still in s.
+func (f *Finder) Seek(offset int) {
+	f.offset = offset
+}
+
+// Find next value, returns false if there are no more values.
+func (f *Finder) Next() bool {
+	for f.offset < len(f.buffer) {
+		m := f.sc.findMatch(f.buffer, f.offset, f.kind)
+		if m.Found() {
+			if f.sc.isTrivial(m) {
+				f.offset = f.sc.skipTrivial(m)
+				continue
+			}
+
+			f.offset = m.End
+			f.match = m
+			return true
+		}
+
+		if m.Kind == 0 {
+			// no candidate found
+			break
+		}
+
+		if f.mode == SearchResumeAfterStart || m.Next() <= m.Start {
+			f.offset = m.Start + 1
+
+		} else {
+			f.offset = m.Next()
+		}
+	}
+
+	f.offset = len(f.buffer)
+	return false
+}
+
+// Get the value found by the last call of Next.
+func (f *Finder) Match() Match {
+	return f.match
+}
@@ -0,0 +1,298 @@
+package findjson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func finderCollect(f *Finder) []string {
+	got := make([]string, 0)
+	for f.Next() {
+		got = append(got, string(f.Match().Bytes()))
+	}
+
+	return got
+}
+
+func checkFinderResult(t *testing.T, got []string, exp []string) {
+	t.Helper()
+	if len(got) != len(exp) {
+		t.Fatalf("got %q, expected %q", got, exp)
+	}
+
+	for i, v := range exp {
+		if got[i] != v {
+			t.Errorf("exp[%d](%s) != got[%d](%s)", i, v, i, got[i])
+		}
+	}
+}
+
+func TestFinderResumeAtFailure(t *testing.T) {
+	s := []byte(`{"x": {"a": 1} oops} [1, 2] {"b": [3]}`)
+	f := NewFinder(s, JsonValueObject|JsonValueArray, nil)
+
+	got := finderCollect(f)
+	checkFinderResult(t, got, []string{`[1, 2]`, `{"b": [3]}`})
+
+	if f.Next() {
+		t.Errorf("Next() returns true after end")
+	}
+}
+
+func TestFinderSeek(t *testing.T) {
+	s := []byte(`[1] [2, 3] {"a": [4]}`)
+	f := NewFinder(s, JsonValueArray, nil)
+	f.Seek(5)
+
+	got := finderCollect(f)
+	checkFinderResult(t, got, []string{`[4]`})
+
+	f.Seek(0)
+	if !f.Next() || f.Match().Start != 0 {
+		t.Errorf("Next() after Seek(0) finds %v", f.Match())
+	}
+}
+
+func TestFinderResumeAfterStart(t *testing.T) {
+	s := []byte(`{"x": {"a": 1} oops} [1, 2] {"b": [3]}`)
+	f := NewFinder(s, JsonValueObject|JsonValueArray, nil)
+	f.SetMode(SearchResumeAfterStart)
+
+	got := finderCollect(f)
+	checkFinderResult(t, got, []string{`{"a": 1}`, `[1, 2]`, `{"b": [3]}`})
+}
+
+func TestFinderResumeAfterStartNested(t *testing.T) {
+	s := []byte(`[[1, [2, {"c": [3, 4]}]], x`)
+	f := NewFinder(s, JsonValueArray, nil)
+	f.SetMode(SearchResumeAfterStart)
+
+	got := make([]string, 0)
+	depth := make([]int, 0)
+	for f.Next() {
+		m := f.Match()
+		got = append(got, string(m.Bytes()))
+		depth = append(depth, m.Depth)
+	}
+
+	checkFinderResult(t, got, []string{`[1, [2, {"c": [3, 4]}]]`})
+	if depth[0] != 4 {
+		t.Errorf("depth of memoized value is %d", depth[0])
+	}
+}
+
+func TestFinderMemoizedDepth(t *testing.T) {
+	// the outer array fails, depth of inner array comes from memo.
+	s := []byte(`[[1, [2, {"c": [3]}]] x`)
+	f := NewFinder(s, JsonValueArray, nil)
+	f.SetMode(SearchResumeAfterStart)
+
+	if !f.Next() {
+		t.Fatalf("Next() returns false")
+	}
+
+	m := f.Match()
+	if string(m.Bytes()) != `[1, [2, {"c": [3]}]]` || m.Depth != 4 {
+		t.Errorf("Match() returns %s, depth %d", m.Bytes(), m.Depth)
+	}
+}
+
+func TestFinderResumeAfterStartMaxDepth(t *testing.T) {
+	// failures of too deep are not memoized, inner values are found as root values.
+	s := []byte(`[[[[1]]]]`)
+	f := NewFinder(s, JsonValueArray, &Options{MaxDepth: 2})
+	f.SetMode(SearchResumeAfterStart)
+
+	got := finderCollect(f)
+	checkFinderResult(t, got, []string{`[[1]]`})
+}
+
+func TestFinderResumeAfterStartMaxValueLength(t *testing.T) {
+	//           0         1         2
+	//           0123456789012345678901234567
+	s := []byte(`[1, [2, 3], [4, 5, 6, 7, 8]]`)
+	f := NewFinder(s, JsonValueArray, &Options{MaxValueLength: 15})
+	f.SetMode(SearchResumeAfterStart)
+
+	got := finderCollect(f)
+	checkFinderResult(t, got, []string{`[2, 3]`, `[4, 5, 6, 7, 8]`})
+}
+
+func TestFinderResumeAfterStartLinear(t *testing.T) {
+	// O(n^2) without memo, never ends in time.
+	inputs := [][]byte{
+		bytes.Repeat([]byte("["), 200000),
+		bytes.Repeat([]byte(`{"a":`), 100000),
+		[]byte(strings.Repeat(`[1, `, 100000) + `x`),
+	}
+
+	for _, s := range inputs {
+		f := NewFinder(s, JsonValueArray|JsonValueObject, nil)
+		f.SetMode(SearchResumeAfterStart)
+		if got := finderCollect(f); len(got) != 0 {
+			t.Errorf("got %d values", len(got))
+		}
+	}
+
+	// candidates inside failed numbers
+	inputs = [][]byte{
+		[]byte(strings.Repeat("1", 200000) + "e"),
+		[]byte("-" + strings.Repeat("1", 100000) + "." + strings.Repeat("2", 100000) + "e+"),
+	}
+
+	for _, s := range inputs {
+		f := NewFinder(s, JsonValueAll, nil)
+		f.SetMode(SearchResumeAfterStart)
+		if got := finderCollect(f); len(got) != 0 {
+			t.Errorf("got %d values", len(got))
+		}
+	}
+
+	// candidates inside unclosed comments and strings
+	inputs = [][]byte{
+		[]byte(`"` + strings.Repeat(`\"`, 100000)),
+		[]byte(strings.Repeat(`[/*`, 100000)),
+		[]byte(strings.Repeat(`[//`, 100000)),
+		[]byte(`/*` + strings.Repeat(`[/*`, 100000) + `*/`),
+	}
+
+	for _, s := range inputs {
+		f := NewFinder(s, JsonValueAll, &Options{Style: JavaScriptLiteralStyle})
+		f.SetMode(SearchResumeAfterStart)
+		if got := finderCollect(f); len(got) != 0 {
+			t.Errorf("got %d values", len(got))
+		}
+	}
+}
+
+func TestFinderResumeAfterStartInNumbers(t *testing.T) {
+	inputs := []string{
+		`100e 1.05e+ -0.5e 12.x 0x1Fe 1.5e 2`,
+		`[1, 20.e] 3007e`,
+	}
+
+	for _, v := range inputs {
+		for _, style := range []Style{NormativeStyle, StyleSpecialNumbers} {
+			f := NewFinder([]byte(v), JsonValueAll, &Options{Style: style})
+			f.SetMode(SearchResumeAfterStart)
+			got := finderCollect(f)
+
+			// results without memo
+			f = NewFinder([]byte(v), JsonValueAll, &Options{Style: style})
+			f.SetMode(SearchResumeAfterStart)
+			f.sc.memo = nil
+			checkFinderResult(t, got, finderCollect(f))
+		}
+	}
+}
+
+func TestFinderResumeAfterStartInComments(t *testing.T) {
+	s := []byte(`[1, /* [2, "a\"] */ 3] // {"b": /* [4] */ 5}` + "\n" + `[/* {"c": 6} /*/ 7]`)
+	f := NewFinder(s, JsonValueArray|JsonValueObject, &Options{Style: JavaScriptLiteralStyle})
+	f.SetMode(SearchResumeAfterStart)
+
+	got := finderCollect(f)
+	checkFinderResult(t, got, []string{
+		`[1, /* [2, "a\"] */ 3]`,
+		`{"b": /* [4] */ 5}`,
+		`[/* {"c": 6} /*/ 7]`,
+	})
+}
+
+func TestFinderResumeAfterStartInStrings(t *testing.T) {
+	s := []byte(`"a \"[1, \"b\"]\" \x {"c": "d"}`)
+	f := NewFinder(s, JsonValueAll, nil)
+	f.SetMode(SearchResumeAfterStart)
+
+	got := finderCollect(f)
+	checkFinderResult(t, got, []string{`1`, `{"c": "d"}`})
+}
+
+func TestFinderThresholds(t *testing.T) {
+	s := []byte(`Step 1 of "a" is [] and {"x": [1, 2, 3]}, then [{"y": 1}, {"z": 2}] and {"k": "v"}.`)
+
+	type thresholdCase struct {
+		options Options
+		exp     []string
+	}
+
+	caseList := []thresholdCase{
+		{Options{}, []string{`1`, `"a"`, `[]`, `{"x": [1, 2, 3]}`, `[{"y": 1}, {"z": 2}]`, `{"k": "v"}`}},
+		{Options{MinValueLength: 10}, []string{`{"x": [1, 2, 3]}`, `[{"y": 1}, {"z": 2}]`, `{"k": "v"}`}},
+		{Options{MinItems: 1}, []string{`{"x": [1, 2, 3]}`, `[{"y": 1}, {"z": 2}]`, `{"k": "v"}`}},
+		{Options{MinItems: 2}, []string{`[1, 2, 3]`, `[{"y": 1}, {"z": 2}]`}},
+		{Options{MinItems: 3}, []string{`[1, 2, 3]`}},
+		{Options{MinDepth: 2}, []string{`{"x": [1, 2, 3]}`, `[{"y": 1}, {"z": 2}]`}},
+		{Options{MinDepth: 2, MinItems: 2}, []string{`[{"y": 1}, {"z": 2}]`}},
+		{Options{MinDepth: 3}, []string{}},
+	}
+
+	for _, c := range caseList {
+		for _, mode := range []SearchMode{SearchResumeAtFailure, SearchResumeAfterStart} {
+			options := c.options
+			f := NewFinder(s, JsonValueAll, &options)
+			f.SetMode(mode)
+			checkFinderResult(t, finderCollect(f), c.exp)
+		}
+	}
+}
+
+func TestFinderThresholdsNested(t *testing.T) {
+	// the root array is skipped, values nested in it are still searched
+	s := []byte(`[{"a":1,"b":2,"c":3}]`)
+	for _, mode := range []SearchMode{SearchResumeAtFailure, SearchResumeAfterStart} {
+		f := NewFinder(s, JsonValueAll, &Options{MinItems: 3})
+		f.SetMode(mode)
+		checkFinderResult(t, finderCollect(f), []string{`{"a":1,"b":2,"c":3}`})
+	}
+
+	m := FindJsonMatchWithOptions(s, 0, JsonValueAll, &Options{MinItems: 3})
+	if !m.Found() || string(m.Bytes()) != `{"a":1,"b":2,"c":3}` {
+		t.Errorf("FindJsonMatchWithOptions(%s) returns %+v", s, m)
+	}
+
+	m = FindJsonMatchWithOptions(s, 0, JsonValueAll, &Options{MinItems: 3, Selection: SelectOutermost})
+	if !m.Found() || m.Start != 1 {
+		t.Errorf("FindJsonMatchWithOptions(%s) with SelectOutermost returns %+v", s, m)
+	}
+
+	// values nested are shorter and shallower, they are not searched
+	s = []byte(`[[[1, 2]], [3]]`)
+	f := NewFinder(s, JsonValueAll, &Options{MinDepth: 4, MinValueLength: 4})
+	checkFinderResult(t, finderCollect(f), []string{})
+
+	// O(n^2) if values nested are scanned again in each level, never ends in time.
+	s = []byte(strings.Repeat(`[`, 100000) + strings.Repeat(`]`, 100000))
+	f = NewFinder(s, JsonValueAll, &Options{MinItems: 2})
+	checkFinderResult(t, finderCollect(f), []string{})
+}
+
+func TestFinderThresholdsMemoized(t *testing.T) {
+	// [1, 2] is memoized when scanned in the failed candidate, its items are reported later
+	s := []byte(`{"a": [1, 2] oops [1, 2]`)
+	f := NewFinder(s, JsonValueArray|JsonValueObject, &Options{MinItems: 2})
+	f.SetMode(SearchResumeAfterStart)
+
+	got := make([]int, 0)
+	for f.Next() {
+		got = append(got, f.Match().Items)
+	}
+
+	if len(got) != 2 || got[0] != 2 || got[1] != 2 {
+		t.Errorf("items of values found are %v", got)
+	}
+}
+
+func TestFindJsonWithThresholds(t *testing.T) {
+	s := []byte(`id 42 [] {"x": 1, "y": 2}`)
+	start, end, err := FindJsonWithOptions(s, 0, JsonValueAll, &Options{MinItems: 1})
+	if err != nil || string(s[start:end]) != `{"x": 1, "y": 2}` {
+		t.Errorf("FindJsonWithOptions(%s) returns %d, %d, %v", s, start, end, err)
+	}
+
+	m := FindJsonMatchWithOptions(s, 0, JsonValueAll, &Options{MinItems: 3})
+	if m.Found() || m.Kind != 0 {
+		t.Errorf("FindJsonMatchWithOptions(%s) returns %+v", s, m)
+	}
+}
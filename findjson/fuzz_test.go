@@ -0,0 +1,202 @@
+//go:build go1.18
+// +build go1.18
+
+package findjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// Seeds shared by all fuzz targets, more seeds are in testdata/fuzz.
+var fuzzSeeds = []string{
+	``,
+	` `,
+	`null`,
+	`true`,
+	`-12.5e+3`,
+	`"a\"b\\cé😀"`,
+	`"ab\`,
+	`'single'`,
+	`[1, [2, {"a": null}], "b"]`,
+	`[1, 2,]`,
+	`{"a": 1, "b": [true, false]}`,
+	`{a: 1, /* c */ 'b': 2,} // end`,
+	`{'a': (1, 2), 'b': None}`,
+	`[NaN, -Infinity, 0x1F, .5]`,
+	`<script>var a = {"x": [1, 2]};</script>   `,
+}
+
+var fuzzStyles = []Style{NormativeStyle, JavaScriptLiteralStyle, PythonStyle, styleAllFeatures}
+
+func addFuzzSeeds(f *testing.F) {
+	for _, s := range fuzzSeeds {
+		f.Add([]byte(s))
+	}
+}
+
+// Check invariants of spans returned by scanners, at offset i of s.
+func checkFuzzSpan(t *testing.T, s []byte, i int, start int, end int, err error) {
+	if start < i || end < start || end > len(s) {
+		t.Fatalf("invalid span [%d, %d) from %d of %d bytes, error %v", start, end, i, len(s), err)
+	}
+
+	if e, ok := err.(*JsonError); ok && (e.Offset < 0 || e.Offset > len(s)) {
+		t.Fatalf("error offset %d out of %d bytes", e.Offset, len(s))
+	}
+}
+
+func fuzzScanner(f *testing.F, scanner func(s []byte, i int, style Style) (int, int, error)) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s []byte) {
+		if len(s) == 0 {
+			return
+		}
+
+		for _, style := range fuzzStyles {
+			start, end, err := scanner(s, 0, style)
+			checkFuzzSpan(t, s, 0, start, end, err)
+		}
+	})
+}
+
+func FuzzScanJsonLiteral(f *testing.F) {
+	fuzzScanner(f, func(s []byte, i int, style Style) (int, int, error) {
+		return newJsonScannerWithStyle(style).scanLiteral(s, i)
+	})
+}
+
+func FuzzScanJsonNumber(f *testing.F) {
+	fuzzScanner(f, func(s []byte, i int, style Style) (int, int, error) {
+		return newJsonScannerWithStyle(style).scanNumber(s, i)
+	})
+}
+
+func FuzzScanJsonString(f *testing.F) {
+	fuzzScanner(f, scanJsonStringWithStyle)
+}
+
+func FuzzScanJsonArray(f *testing.F) {
+	fuzzScanner(f, scanJsonArray)
+}
+
+func FuzzScanJsonObject(f *testing.F) {
+	fuzzScanner(f, scanJsonObject)
+}
+
+func FuzzFindJson(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s []byte) {
+		for _, style := range fuzzStyles {
+			i := 0
+			for i < len(s) {
+				start, end, err := FindJsonWithOptions(s, i, JsonValueAll, &Options{Style: style})
+				checkFuzzSpan(t, s, i, start, end, err)
+				if end <= i {
+					if err == nil {
+						t.Fatalf("empty value at %d with %s style", i, style)
+					}
+
+					break
+				}
+
+				i = end
+			}
+
+			finder := NewFinder(s, JsonValueAll, &Options{Style: style})
+			finder.SetMode(SearchResumeAfterStart)
+			for finder.Next() {
+				m := finder.Match()
+				checkFuzzSpan(t, s, 0, m.Start, m.End, nil)
+			}
+		}
+	})
+}
+
+// Differential check against encoding/json, on the whole input and on spans found.
+func FuzzNormativeAgainstEncodingJson(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s []byte) {
+		if bytes.Count(s, []byte("["))+bytes.Count(s, []byte("{")) > 9000 {
+			// encoding/json limits nesting depth to 10000
+			return
+		}
+
+		valid := json.Valid(s)
+		accepted := false
+		if i := jumpNextNonWhiteSpace(s, 0); i < len(s) {
+			_, end, err := newJsonScanner(nil).scanValue(s, i, JsonValueAll)
+			accepted = err == nil && jumpNextNonWhiteSpace(s, end) == len(s)
+		}
+
+		if valid != accepted {
+			t.Fatalf("json.Valid(%q) = %t, accepted in NormativeStyle = %t", s, valid, accepted)
+		}
+
+		i := 0
+		for i < len(s) {
+			start, end, err := FindJson(s, i, JsonValueAll)
+			if err == nil && !json.Valid(s[start:end]) {
+				t.Fatalf("span %q found in %q is not valid", s[start:end], s)
+			}
+
+			if end <= i {
+				break
+			}
+
+			i = end
+		}
+	})
+}
+
+// Repaired values of truncated inputs are accepted, and valid for encoding/json in NormativeStyle.
+func FuzzRepairJson(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s []byte) {
+		if bytes.Count(s, []byte("["))+bytes.Count(s, []byte("{")) > 9000 {
+			return
+		}
+
+		for _, style := range fuzzStyles {
+			for n := len(s); n >= 0 && n+64 >= len(s); n-- {
+				options := &Options{Style: style}
+				repaired, report, err := RepairJson(s[:n], 0, JsonValueAll, options)
+				if err != nil {
+					continue
+				}
+
+				checkFuzzSpan(t, s[:n], 0, report.Start, report.End, nil)
+				_, end, err := newJsonScanner(options).scanValue(repaired, 0, JsonValueAll)
+				if err != nil || end != len(repaired) {
+					t.Fatalf("%q repaired from %q is not accepted in %s style", repaired, s[:n], style)
+				}
+
+				if style == NormativeStyle && !json.Valid(repaired) {
+					t.Fatalf("%q repaired from %q is not valid", repaired, s[:n])
+				}
+			}
+		}
+	})
+}
+
+func FuzzParseJsonTolerant(f *testing.F) {
+	addFuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, s []byte) {
+		if bytes.Count(s, []byte("["))+bytes.Count(s, []byte("{")) > 9000 {
+			return
+		}
+
+		for _, style := range fuzzStyles {
+			r, err := ParseJsonTolerant(s, 0, JsonValueAll, &Options{Style: style})
+			if err != nil {
+				continue
+			}
+
+			checkFuzzSpan(t, s, 0, r.Start, r.End, nil)
+			if !json.Valid(r.Json) {
+				t.Fatalf("%q parsed from %q is not valid", r.Json, s)
+			}
+		}
+	})
+}
@@ -0,0 +1,553 @@
+package findjson
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Where a value is found in HTML document.
+type HtmlSource int
+
+const (
+	HtmlSourceScript    = HtmlSource(1) // body of <script> element
+	HtmlSourceAttribute = HtmlSource(2) // attribute value, entities decoded
+)
+
+func (s HtmlSource) String() string {
+	switch s {
+	case HtmlSourceScript:
+		return "script"
+
+	case HtmlSourceAttribute:
+		return "attribute"
+	}
+
+	return "unknown"
+}
+
+// Options of searching HTML documents, a nil *HtmlOptions searches all <script> bodies and no
+// attribute values.
+type HtmlOptions struct {
+	// Search <script> elements of these types only, e.g. "application/json" and
+	// "application/ld+json", compared case-insensitively, parameters like "; charset=utf-8" are
+	// ignored. Empty for any type, including <script> without type attribute.
+	ScriptTypes []string
+
+	// Search <script> elements of these ids only, e.g. "__NEXT_DATA__", compared
+	// case-sensitively. Empty for any id.
+	ScriptIds []string
+
+	// Do not search <script> bodies, for searching attribute values only.
+	NoScripts bool
+
+	// Names of attributes whose values are searched, compared case-insensitively. A name ends
+	// with '*' matches by prefix, e.g. "data-*" matches all data attributes, "*" matches all
+	// attributes. Empty for no attributes.
+	Attributes []string
+}
+
+// Value found in HTML document.
+type HtmlMatch struct {
+	Start int // start of value in document
+	End   int // end of value in document, exclusive, entities are included as a whole
+
+	Source    HtmlSource
+	Tag       string // name of element, lower-cased
+	Id        string // id of element, entities decoded, empty if not present
+	Attribute string // name of attribute, lower-cased, empty for <script> bodies
+
+	// Match in the decoded text of script body or attribute value, Bytes() returns the value
+	// with entities decoded.
+	Match Match
+}
+
+// Text to search, decoded from s[start:end] of document.
+type htmlSegment struct {
+	source    HtmlSource
+	tag       string
+	id        string
+	attribute string
+	text      []byte
+
+	// offsets[k] is the offset in document of text[k], offsets[len(text)] is the end of
+	// segment. nil if text is not decoded, text[k] is at start+k.
+	offsets []int
+	start   int
+}
+
+func (g *htmlSegment) offset(k int) int {
+	if g.offsets == nil {
+		return g.start + k
+	}
+
+	return g.offsets[k]
+}
+
+// Name and raw value of attribute in a start tag.
+type htmlAttribute struct {
+	name       string
+	valueStart int
+	valueEnd   int
+	hasValue   bool
+}
+
+var htmlEntities = map[string]rune{
+	"quot": '"',
+	"amp":  '&',
+	"apos": '\'',
+	"lt":   '<',
+	"gt":   '>',
+	"nbsp": '\u00a0',
+}
+
+// Decode HTML character reference at s[i], which is '&'. Returns the rune and the end of the
+// reference, or -1 if it is not a reference. The terminating ';' is required.
+func decodeHtmlEntity(s []byte, i int) (rune, int) {
+	semicolon := bytes.IndexByte(s[i:], ';')
+	if semicolon < 2 || semicolon > 10 {
+		return 0, -1
+	}
+
+	name := string(s[i+1 : i+semicolon])
+	end := i + semicolon + 1
+	if name[0] != '#' {
+		if r, found := htmlEntities[name]; found {
+			return r, end
+		}
+
+		return 0, -1
+	}
+
+	base := 10
+	digits := name[1:]
+	if len(digits) > 0 && (digits[0] == 'x' || digits[0] == 'X') {
+		base = 16
+		digits = digits[1:]
+	}
+
+	if len(digits) == 0 || digits[0] == '+' || digits[0] == '-' {
+		return 0, -1
+	}
+
+	v, err := strconv.ParseUint(digits, base, 32)
+	if err != nil {
+		return 0, -1
+	}
+
+	r := rune(v)
+	if r == 0 || !utf8.ValidRune(r) {
+		r = utf8.RuneError
+	}
+
+	return r, end
+}
+
+// Decode HTML character references in s[start:end], returns the text and offsets in s of
+// each byte of it.
+func decodeHtmlText(s []byte, start int, end int) ([]byte, []int) {
+	if bytes.IndexByte(s[start:end], '&') < 0 {
+		return s[start:end], nil
+	}
+
+	text := make([]byte, 0, end-start)
+	offsets := make([]int, 0, end-start+1)
+	b := s[:end]
+	var buf [utf8.UTFMax]byte
+	i := start
+	for i < end {
+		if b[i] == '&' {
+			if r, next := decodeHtmlEntity(b, i); next > 0 {
+				n := utf8.EncodeRune(buf[:], r)
+				text = append(text, buf[:n]...)
+				for k := 0; k < n; k++ {
+					offsets = append(offsets, i)
+				}
+
+				i = next
+				continue
+			}
+		}
+
+		text = append(text, b[i])
+		offsets = append(offsets, i)
+		i++
+	}
+
+	offsets = append(offsets, end)
+	return text, offsets
+}
+
+func isHtmlSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isHtmlNameChar(c byte) bool {
+	return !isHtmlSpace(c) && c != '/' && c != '>' && c != '='
+}
+
+func isAsciiLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Find prefix in s[i:] case-insensitively, prefix is lower-cased and starts with a non-letter.
+func bufferFindFold(s []byte, i int, prefix string) int {
+	for i+len(prefix) <= len(s) {
+		k := bytes.IndexByte(s[i:], prefix[0])
+		if k < 0 {
+			break
+		}
+
+		i += k
+		if i+len(prefix) <= len(s) && strings.EqualFold(string(s[i:i+len(prefix)]), prefix) {
+			return i
+		}
+
+		i++
+	}
+
+	return -1
+}
+
+// Find end tag of raw text element, e.g. </script>, returns len(s) if not found.
+func findHtmlEndTag(s []byte, i int, name string) int {
+	prefix := "</" + name
+	for {
+		k := bufferFindFold(s, i, prefix)
+		if k < 0 {
+			return len(s)
+		}
+
+		e := k + len(prefix)
+		if e >= len(s) || isHtmlSpace(s[e]) || s[e] == '/' || s[e] == '>' {
+			return k
+		}
+
+		i = e
+	}
+}
+
+// Scan start tag at s[i], which is '<' followed by a letter. Returns lower-cased tag name,
+// attributes, whether it is self-closing and the end of tag.
+func scanHtmlStartTag(s []byte, i int) (string, []htmlAttribute, bool, int) {
+	l := len(s)
+	j := i + 1
+	for j < l && isHtmlNameChar(s[j]) {
+		j++
+	}
+
+	name := strings.ToLower(string(s[i+1 : j]))
+	attributes := make([]htmlAttribute, 0, 4)
+	selfClosing := false
+	for j < l {
+		for j < l && (isHtmlSpace(s[j]) || s[j] == '/') {
+			selfClosing = s[j] == '/'
+			j++
+		}
+
+		if j >= l {
+			break
+		}
+
+		if s[j] == '>' {
+			return name, attributes, selfClosing, j + 1
+		}
+
+		selfClosing = false
+		k := j
+		j++ // a leading '=' is part of name
+		for j < l && isHtmlNameChar(s[j]) {
+			j++
+		}
+
+		attribute := htmlAttribute{name: strings.ToLower(string(s[k:j]))}
+		v := j
+		for v < l && isHtmlSpace(s[v]) {
+			v++
+		}
+
+		if v < l && s[v] == '=' {
+			v++
+			for v < l && isHtmlSpace(s[v]) {
+				v++
+			}
+
+			attribute.hasValue = true
+			if v < l && (s[v] == '"' || s[v] == '\'') {
+				e := bytes.IndexByte(s[v+1:], s[v])
+				if e < 0 {
+					e = l - v - 1
+				}
+
+				attribute.valueStart = v + 1
+				attribute.valueEnd = v + 1 + e
+				j = attribute.valueEnd + 1
+
+			} else {
+				e := v
+				for e < l && !isHtmlSpace(s[e]) && s[e] != '>' {
+					e++
+				}
+
+				attribute.valueStart = v
+				attribute.valueEnd = e
+				j = e
+			}
+		}
+
+		attributes = append(attributes, attribute)
+	}
+
+	return name, attributes, selfClosing, l
+}
+
+func (o *HtmlOptions) matchAttribute(name string) bool {
+	for _, pattern := range o.Attributes {
+		pattern = strings.ToLower(pattern)
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(name, pattern[:len(pattern)-1]) {
+				return true
+			}
+
+		} else if name == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check whether v is in list, or list is empty.
+func matchAnyOf(list []string, v string, fold bool) bool {
+	if len(list) == 0 {
+		return true
+	}
+
+	for _, item := range list {
+		if item == v || (fold && strings.EqualFold(strings.TrimSpace(item), v)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (o *HtmlOptions) matchScript(scriptType string, id string) bool {
+	if o.NoScripts {
+		return false
+	}
+
+	return matchAnyOf(o.ScriptTypes, htmlMimeEssence(scriptType), true) &&
+		matchAnyOf(o.ScriptIds, id, false)
+}
+
+// Get MIME type without parameters, e.g. "application/json" of
+// "application/json; charset=utf-8".
+func htmlMimeEssence(v string) string {
+	if k := strings.IndexByte(v, ';'); k >= 0 {
+		v = v[:k]
+	}
+
+	return strings.TrimSpace(v)
+}
+
+// Tokenizer of HTML document, produces segments to search.
+type htmlTokenizer struct {
+	document []byte
+	options  *HtmlOptions
+	offset   int
+	pending  []htmlSegment
+}
+
+// Get attribute value, entities decoded.
+func (t *htmlTokenizer) attributeValue(attributes []htmlAttribute, name string) string {
+	for _, a := range attributes {
+		if a.name == name && a.hasValue {
+			text, _ := decodeHtmlText(t.document, a.valueStart, a.valueEnd)
+			return string(text)
+		}
+	}
+
+	return ""
+}
+
+// Skip markup not containing values, e.g. comments, end tags and doctype, at s[i], which is
+// '<'. Returns the end of markup, or -1 if s[i] is not a start of markup.
+func skipHtmlMarkup(s []byte, i int) int {
+	l := len(s)
+	if i+1 >= l {
+		return -1
+	}
+
+	c := s[i+1]
+	if c == '!' && bytes.HasPrefix(s[i:], []byte("<!--")) {
+		e := bytes.Index(s[i+4:], []byte("-->"))
+		if e < 0 {
+			return l
+		}
+
+		return i + 4 + e + 3
+	}
+
+	if c == '!' || c == '?' || (c == '/' && i+2 < l && isAsciiLetter(s[i+2])) {
+		e := bytes.IndexByte(s[i+1:], '>')
+		if e < 0 {
+			return l
+		}
+
+		return i + 1 + e + 1
+	}
+
+	return -1
+}
+
+// Get next segment to search, returns false at the end of document.
+func (t *htmlTokenizer) next() (htmlSegment, bool) {
+	s := t.document
+	l := len(s)
+	for len(t.pending) == 0 && t.offset < l {
+		k := bytes.IndexByte(s[t.offset:], '<')
+		if k < 0 {
+			t.offset = l
+			break
+		}
+
+		i := t.offset + k
+		if end := skipHtmlMarkup(s, i); end > 0 {
+			t.offset = end
+			continue
+		}
+
+		if i+1 >= l || !isAsciiLetter(s[i+1]) {
+			t.offset = i + 1
+			continue
+		}
+
+		name, attributes, selfClosing, end := scanHtmlStartTag(s, i)
+		t.offset = end
+		id := t.attributeValue(attributes, "id")
+		for _, a := range attributes {
+			if a.hasValue && t.options.matchAttribute(a.name) {
+				text, offsets := decodeHtmlText(s, a.valueStart, a.valueEnd)
+				t.pending = append(t.pending, htmlSegment{
+					source:    HtmlSourceAttribute,
+					tag:       name,
+					id:        id,
+					attribute: a.name,
+					text:      text,
+					offsets:   offsets,
+					start:     a.valueStart,
+				})
+			}
+		}
+
+		if selfClosing || (name != "script" && name != "style") {
+			continue
+		}
+
+		// raw text elements, the body ends at the first end tag
+		bodyEnd := findHtmlEndTag(s, end, name)
+
+		t.offset = bodyEnd
+		if name == "script" && t.options.matchScript(t.attributeValue(attributes, "type"), id) {
+			t.pending = append(t.pending, htmlSegment{
+				source: HtmlSourceScript,
+				tag:    name,
+				id:     id,
+				text:   s[end:bodyEnd],
+				start:  end,
+			})
+		}
+	}
+
+	if len(t.pending) == 0 {
+		return htmlSegment{}, false
+	}
+
+	g := t.pending[0]
+	t.pending = t.pending[1:]
+	return g, true
+}
+
+// HtmlFinder finds JSON values in <script> bodies and attribute values of HTML document, in
+// document order.
+//
+//	f := NewHtmlFinder(s, JsonValueObject, &HtmlOptions{ScriptIds: []string{"__NEXT_DATA__"}}, nil)
+//	for f.Next() {
+//		m := f.Match()
+//		...
+//	}
+type HtmlFinder struct {
+	tokenizer htmlTokenizer
+	kind      JsonValueKind
+	options   *Options
+	segment   htmlSegment
+	finder    *Finder
+	match     HtmlMatch
+}
+
+// Create finder of values of kind in HTML document s, with html options and scanning options,
+// nil for default.
+func NewHtmlFinder(s []byte, kind JsonValueKind, html *HtmlOptions, options *Options) *HtmlFinder {
+	if html == nil {
+		html = &HtmlOptions{}
+	}
+
+	f := &HtmlFinder{
+		tokenizer: htmlTokenizer{
+			document: s,
+			options:  html,
+		},
+		kind:    kind,
+		options: options,
+	}
+
+	return f
+}
+
+// Find next value, returns false if there are no more values.
+func (f *HtmlFinder) Next() bool {
+	for {
+		if f.finder != nil && f.finder.Next() {
+			m := f.finder.Match()
+			g := &f.segment
+			f.match = HtmlMatch{
+				Start:     g.offset(m.Start),
+				End:       g.offset(m.End),
+				Source:    g.source,
+				Tag:       g.tag,
+				Id:        g.id,
+				Attribute: g.attribute,
+				Match:     m,
+			}
+
+			return true
+		}
+
+		segment, ok := f.tokenizer.next()
+		if !ok {
+			f.finder = nil
+			return false
+		}
+
+		f.segment = segment
+		f.finder = NewFinder(segment.text, f.kind, f.options)
+	}
+}
+
+// Get the value found by the last call of Next.
+func (f *HtmlFinder) Match() HtmlMatch {
+	return f.match
+}
+
+// Find all JSON values in <script> bodies and attribute values of HTML document s.
+func FindJsonInHtml(s []byte, kind JsonValueKind, html *HtmlOptions, options *Options) []HtmlMatch {
+	result := make([]HtmlMatch, 0)
+	f := NewHtmlFinder(s, kind, html, options)
+	for f.Next() {
+		result = append(result, f.Match())
+	}
+
+	return result
+}
@@ -0,0 +1,178 @@
+package findjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+const htmlSample = `<!DOCTYPE html>
+<html>
+<head>
+<!-- <script>var commented = [0];</script> -->
+<script src="app.js"></script>
+<script type="application/ld+json">{"@type": "Person", "name": "a"}</script>
+<SCRIPT id="__NEXT_DATA__" type="application/json">{"props": {"page": 1}}</SCRIPT>
+<script>var list = [1, 2]; /* </scripts> </scrip */ var x = "</SCRIPT >[3]";</script>
+<style>p { color: red } [data-x] {}</style>
+</head>
+<body>
+<div data-props='{"id": 3}' title="[4]" data-empty data-list=[5]></div>
+<div data-props="{&quot;name&quot;: &quot;&#x4e2d;&amp;&quot;}">[6]</div>
+</body>
+</html>`
+
+func htmlCollect(s []byte, kind JsonValueKind, html *HtmlOptions) ([]string, []string) {
+	decoded := make([]string, 0)
+	raw := make([]string, 0)
+	for _, m := range FindJsonInHtml(s, kind, html, nil) {
+		decoded = append(decoded, string(m.Match.Bytes()))
+		raw = append(raw, string(s[m.Start:m.End]))
+	}
+
+	return decoded, raw
+}
+
+func TestFindJsonInHtmlScripts(t *testing.T) {
+	s := []byte(htmlSample)
+
+	got, raw := htmlCollect(s, JsonValueObject|JsonValueArray, nil)
+	exp := []string{
+		`{"@type": "Person", "name": "a"}`,
+		`{"props": {"page": 1}}`,
+		`[1, 2]`,
+	}
+	checkFinderResult(t, got, exp)
+	checkFinderResult(t, raw, exp)
+
+	got, _ = htmlCollect(s, JsonValueObject, &HtmlOptions{ScriptTypes: []string{"Application/JSON"}})
+	checkFinderResult(t, got, []string{`{"props": {"page": 1}}`})
+
+	// MIME parameters are ignored
+	p := []byte(`<script type="application/json; charset=utf-8">{"a": 1}</script>`)
+	got, _ = htmlCollect(p, JsonValueObject, &HtmlOptions{ScriptTypes: []string{"application/json"}})
+	checkFinderResult(t, got, []string{`{"a": 1}`})
+
+	got, _ = htmlCollect(s, JsonValueObject, &HtmlOptions{ScriptIds: []string{"__NEXT_DATA__"}})
+	checkFinderResult(t, got, []string{`{"props": {"page": 1}}`})
+
+	got, _ = htmlCollect(s, JsonValueObject, &HtmlOptions{ScriptIds: []string{"__next_data__"}})
+	checkFinderResult(t, got, []string{})
+
+	// scripts without id
+	got, _ = htmlCollect(s, JsonValueArray|JsonValueString, &HtmlOptions{ScriptIds: []string{""}})
+	checkFinderResult(t, got, []string{`"@type"`, `"Person"`, `"name"`, `"a"`, `[1, 2]`})
+}
+
+func TestFindJsonInHtmlAttributes(t *testing.T) {
+	s := []byte(htmlSample)
+	html := &HtmlOptions{
+		NoScripts:  true,
+		Attributes: []string{"DATA-*"},
+	}
+
+	ms := FindJsonInHtml(s, JsonValueObject|JsonValueArray, html, nil)
+	if len(ms) != 3 {
+		t.Fatalf("FindJsonInHtml() returns %d values", len(ms))
+	}
+
+	exp := []struct {
+		decoded   string
+		raw       string
+		attribute string
+	}{
+		{`{"id": 3}`, `{"id": 3}`, "data-props"},
+		{`[5]`, `[5]`, "data-list"},
+		{`{"name": "中&"}`, `{&quot;name&quot;: &quot;&#x4e2d;&amp;&quot;}`, "data-props"},
+	}
+
+	for i, e := range exp {
+		m := ms[i]
+		if string(m.Match.Bytes()) != e.decoded || string(s[m.Start:m.End]) != e.raw {
+			t.Errorf("match[%d] is %s, raw %s", i, m.Match.Bytes(), s[m.Start:m.End])
+		}
+
+		if m.Source != HtmlSourceAttribute || m.Tag != "div" || m.Attribute != e.attribute {
+			t.Errorf("match[%d] is in %s of <%s %s>", i, m.Source, m.Tag, m.Attribute)
+		}
+	}
+
+	got, _ := htmlCollect(s, JsonValueArray, &HtmlOptions{NoScripts: true, Attributes: []string{"*"}})
+	checkFinderResult(t, got, []string{`[4]`, `[5]`})
+}
+
+func TestFindJsonInHtmlUnclosed(t *testing.T) {
+	inputs := []string{
+		`<script>[1, 2]`,
+		`<div data-a='[1, 2]`,
+		`<div data-a=[1,2]`,
+		`<div data-a="&quot;abc&quo`,
+		`<div data-a="&#xffffffff;&#0;&#-1;&#;&;"`,
+		`<!-- [1]`,
+		`<!doctype [1]`,
+		`<`,
+		`< [1]`,
+	}
+
+	html := &HtmlOptions{Attributes: []string{"data-a"}}
+	for _, v := range inputs {
+		s := []byte(v)
+		for _, m := range FindJsonInHtml(s, JsonValueAll, html, nil) {
+			if m.Start < 0 || m.End > len(s) || m.Start > m.End {
+				t.Errorf("FindJsonInHtml(%s) returns span [%d, %d)", v, m.Start, m.End)
+			}
+		}
+	}
+
+	got, _ := htmlCollect([]byte(inputs[0]), JsonValueArray, nil)
+	checkFinderResult(t, got, []string{`[1, 2]`})
+
+	got, _ = htmlCollect([]byte(inputs[2]), JsonValueArray, html)
+	checkFinderResult(t, got, []string{`[1,2]`})
+}
+
+func TestDecodeHtmlText(t *testing.T) {
+	s := []byte(`a&lt;&#98;&#X63;&unknown;&amp`)
+	text, offsets := decodeHtmlText(s, 0, len(s))
+	if string(text) != `a<bc&unknown;&amp` {
+		t.Errorf("decodeHtmlText() returns %s", text)
+	}
+
+	if len(offsets) != len(text)+1 || offsets[1] != 1 || offsets[2] != 5 || offsets[3] != 10 ||
+		offsets[len(text)] != len(s) {
+		t.Errorf("decodeHtmlText() returns offsets %v", offsets)
+	}
+}
+
+func TestDecodeHtmlTextNbsp(t *testing.T) {
+	s := []byte(`{&quot;a&quot;: &quot;1&nbsp;kg&quot;}`)
+	text, _ := decodeHtmlText(s, 0, len(s))
+	if string(text) != "{\"a\": \"1\u00a0kg\"}" {
+		t.Errorf("decodeHtmlText() returns %q", text)
+	}
+
+	html := &HtmlOptions{Attributes: []string{"data-*"}}
+	ms := FindJsonInHtml([]byte(`<div data-x="`+string(s)+`"></div>`), JsonValueObject, html, nil)
+	if len(ms) != 1 || string(ms[0].Match.Bytes()) != "{\"a\": \"1\u00a0kg\"}" {
+		t.Errorf("FindJsonInHtml() returns %v", ms)
+	}
+}
+
+func ExampleFindJsonInHtml() {
+	s := []byte(`<html><body>
+<script id="__NEXT_DATA__" type="application/json">{"page": "/"}</script>
+<div data-props="{&quot;id&quot;: 1}"></div>
+</body></html>`)
+
+	html := &HtmlOptions{
+		ScriptIds:  []string{"__NEXT_DATA__"},
+		Attributes: []string{"data-props"},
+	}
+
+	for _, m := range FindJsonInHtml(s, JsonValueObject, html, nil) {
+		fmt.Printf("%s %s [%d, %d)\n", m.Source, m.Match.Bytes(), m.Start, m.End)
+	}
+
+	// Output:
+	// script {"page": "/"} [64, 77)
+	// attribute {"id": 1} [104, 123)
+}
@@ -0,0 +1,173 @@
+package findjson
+
+import (
+	"bytes"
+)
+
+// Check whether name at s[i:j] is a whole identifier path, e.g. cb in "x.cb(" or "window['cb']",
+// but not in "mycb(" or "cb2(". Returns end of name, including closing quote and bracket.
+func identifierAt(s []byte, i int, j int) (bool, int) {
+	l := len(s)
+	if i >= 2 && (s[i-1] == jsonQuote || s[i-1] == jsonSingleQuote) && s[i-2] == jsonLBracket {
+		// bracket notation, e.g. window["name"]
+		if j+1 < l && s[j] == s[i-1] && s[j+1] == jsonRBracket {
+			return true, j + 2
+		}
+
+		return false, j
+	}
+
+	if i > 0 && isIdentifierPart(s[i-1]) && isIdentifierPart(s[i]) {
+		return false, j
+	}
+
+	if j < l && isIdentifierPart(s[j-1]) && isIdentifierPart(s[j]) {
+		return false, j
+	}
+
+	return true, j
+}
+
+// Find each occurrence of name in s as a whole identifier, start from offset i, and try to
+// get a value with try, until try returns nil error. Returns the first error if all fail.
+func findNamedValue(s []byte, i int, name string, what string,
+	try func(j int) (int, int, error)) (int, int, error) {
+
+	if len(name) == 0 {
+		return i, i, NewJsonError(i, "empty %s name", what)
+	}
+
+	var firstErr error
+	firstStart, firstEnd := i, len(s)
+	pattern := []byte(name)
+	for i < len(s) {
+		k := bytes.Index(s[i:], pattern)
+		if k < 0 {
+			break
+		}
+
+		start := i + k
+		i = start + 1
+		ok, end := identifierAt(s, start, start+len(pattern))
+		if !ok {
+			continue
+		}
+
+		vStart, vEnd, err := try(end)
+		if err == nil {
+			return vStart, vEnd, nil
+		}
+
+		if firstErr == nil {
+			firstStart, firstEnd, firstErr = vStart, vEnd, err
+		}
+	}
+
+	if firstErr == nil {
+		firstErr = NewJsonError(len(s), "%s '%s' not found", what, name)
+	}
+
+	return firstStart, firstEnd, firstErr
+}
+
+func jsonpOptions(options *Options) *Options {
+	if options == nil {
+		options = &Options{Style: JavaScriptLiteralStyle}
+	}
+
+	return options
+}
+
+// Scan a value of kind at s[i], or after white spaces and comments.
+func (sc *jsonScanner) scanValueAfterSpace(s []byte, i int, kind JsonValueKind) (int, int, error) {
+	j := sc.skipSpace(s, i)
+	if j >= len(s) {
+		return j, j, NewJsonError(j, "expect %s, got 'EOF'", kind)
+	}
+
+	return sc.scanRootValue(s, j, kind)
+}
+
+// Find the first argument of JSONP callback name in s, start from offset i, e.g. {"a": 1} in
+// `cb({"a": 1})`, with options, nil for JavaScriptLiteralStyle. Name may be a path, e.g.
+// "jQuery.cb", and is matched as a whole identifier, "cb" does not match "mycb(".
+func FindJsonpWithOptions(s []byte, i int, name string, kind JsonValueKind,
+	options *Options) (int, int, error) {
+
+	sc := newJsonScanner(jsonpOptions(options))
+	if !sc.options.Style.IsValid() {
+		return i, i, NewJsonError(i, "unknown style %d", sc.options.Style)
+	}
+
+	try := func(j int) (int, int, error) {
+		j = sc.skipSpace(s, j)
+		if j >= len(s) || s[j] != jsonLParen {
+			v := bufferFindSample(s, j, 1)
+			return j, j, NewJsonError(j, "expect '(', got '%s'", v)
+		}
+
+		start, end, err := sc.scanValueAfterSpace(s, j+1, kind)
+		if err != nil {
+			return start, end, err
+		}
+
+		k := sc.skipSpace(s, end)
+		if k >= len(s) || (s[k] != jsonRParen && s[k] != jsonComma) {
+			v := bufferFindSample(s, k, 1)
+			return start, k, NewJsonError(k, "expect ')' or ',', got '%s'", v)
+		}
+
+		return start, end, nil
+	}
+
+	return findNamedValue(s, i, name, "callback", try)
+}
+
+// Find the first argument of JSONP callback name in s, start from offset i, in
+// JavaScriptLiteralStyle.
+func FindJsonp(s []byte, i int, name string) (int, int, error) {
+	return FindJsonpWithOptions(s, i, name, JsonValueAll, nil)
+}
+
+// Find value assigned to variable or property name in s, start from offset i, e.g. {"a": 1} in
+// `var state = {"a": 1};`, with options, nil for JavaScriptLiteralStyle. Declarations with var,
+// let and const, properties like `window.state = ` and `window["state"] = `, and plain
+// assignments are matched. Name may be a path, e.g. "window.state".
+func FindJsonAssignmentWithOptions(s []byte, i int, name string, kind JsonValueKind,
+	options *Options) (int, int, error) {
+
+	sc := newJsonScanner(jsonpOptions(options))
+	if !sc.options.Style.IsValid() {
+		return i, i, NewJsonError(i, "unknown style %d", sc.options.Style)
+	}
+
+	try := func(j int) (int, int, error) {
+		j = sc.skipSpace(s, j)
+		if j >= len(s) || s[j] != '=' || (j+1 < len(s) && (s[j+1] == '=' || s[j+1] == '>')) {
+			v := bufferFindSample(s, j, 2)
+			return j, j, NewJsonError(j, "expect '=', got '%s'", v)
+		}
+
+		start, end, err := sc.scanValueAfterSpace(s, j+1, kind)
+		if err != nil {
+			return start, end, err
+		}
+
+		// the value is the whole right-hand side, e.g. not [1] of `[1] + [2]`
+		k := sc.skipSpace(s, end)
+		if k < len(s) && s[k] != ';' && s[k] != jsonComma && bytes.IndexAny(s[end:k], "\r\n") < 0 {
+			v := bufferFindSample(s, k, 1)
+			return start, k, NewJsonError(k, "expect ';', ',' or new line, got '%s'", v)
+		}
+
+		return start, end, nil
+	}
+
+	return findNamedValue(s, i, name, "variable", try)
+}
+
+// Find value assigned to variable or property name in s, start from offset i, in
+// JavaScriptLiteralStyle.
+func FindJsonAssignment(s []byte, i int, name string) (int, int, error) {
+	return FindJsonAssignmentWithOptions(s, i, name, JsonValueAll, nil)
+}
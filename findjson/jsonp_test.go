@@ -0,0 +1,133 @@
+package findjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindJsonp(t *testing.T) {
+	type jsonpCase struct {
+		s    string
+		name string
+		exp  string
+	}
+
+	caseList := []jsonpCase{
+		{`cb({"a": 1});`, "cb", `{"a": 1}`},
+		{`/**/ typeof cb === 'function' && cb( /* data */ [1, 2,] );`, "cb", `[1, 2,]`},
+		{`mycb([0]); cb2([0]); cb ({a: 'b'}, 200)`, "cb", `{a: 'b'}`},
+		{`jQuery.cb_1([1]); jQuery.cb_2([2])`, "jQuery.cb_2", `[2]`},
+		{`window["cb"]([3])`, "cb", `[3]`},
+		{`cb(x); cb("ok")`, "cb", `"ok"`},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		start, end, err := FindJsonp(s, 0, c.name)
+		if err != nil || string(s[start:end]) != c.exp {
+			t.Errorf("FindJsonp(%s, %s) returns %d, %d, %v", c.s, c.name, start, end, err)
+		}
+	}
+
+	errorList := []struct {
+		s    string
+		name string
+		err  string
+	}{
+		{`cb({"a": 1})`, "", "JSON error at 0: empty callback name"},
+		{`callback({"a": 1})`, "cb", "JSON error at 18: callback 'cb' not found"},
+		{`cb = [1]`, "cb", "JSON error at 3: expect '(', got '='"},
+		{`cb([1] + [2])`, "cb", "JSON error at 7: expect ')' or ',', got '+'"},
+		{`cb([1, 2`, "cb", "JSON error at 8: expect comma',' or bracket ']', got 'EOF'"},
+		{`cb(`, "cb", "JSON error at 3: expect null|boolean|number|string|array|object, got 'EOF'"},
+	}
+
+	for _, c := range errorList {
+		s := []byte(c.s)
+		start, end, err := FindJsonp(s, 0, c.name)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("FindJsonp(%s, %s) returns %d, %d, %v", c.s, c.name, start, end, err)
+		}
+	}
+
+	s := []byte(`cb({'a': 1})`)
+	if _, _, err := FindJsonpWithOptions(s, 0, "cb", JsonValueObject, &Options{}); err == nil {
+		t.Errorf("FindJsonpWithOptions(%s) in NormativeStyle returns nil error", s)
+	}
+
+	if _, _, err := FindJsonpWithOptions(s, 0, "cb", JsonValueArray, nil); err == nil {
+		t.Errorf("FindJsonpWithOptions(%s) of array returns nil error", s)
+	}
+
+	if _, _, err := FindJsonpWithOptions(s, 0, "cb", JsonValueAll, &Options{Style: -1}); err == nil {
+		t.Errorf("FindJsonpWithOptions(%s) with unknown style returns nil error", s)
+	}
+}
+
+func TestFindJsonAssignment(t *testing.T) {
+	type assignmentCase struct {
+		s    string
+		name string
+		exp  string
+	}
+
+	caseList := []assignmentCase{
+		{`var state = {"a": 1};`, "state", `{"a": 1}`},
+		{`let state={"a": 1}`, "state", `{"a": 1}`},
+		{`const state /* initial */ =` + "\n" + `[1, 2,];`, "state", `[1, 2,]`},
+		{`window.__STATE__ = {a: 'b'};`, "__STATE__", `{a: 'b'}`},
+		{`window.__STATE__ = {a: 'b'};`, "window.__STATE__", `{a: 'b'}`},
+		{`window['__STATE__'] = [1]`, "__STATE__", `[1]`},
+		{`if (state == null) state = [2]`, "state", `[2]`},
+		{`f = state => state; state = [3]`, "state", `[3]`},
+		{`mystate = [0]; state2 = [0]; state = [4]`, "state", `[4]`},
+		{`state = init(); state = [5]`, "state", `[5]`},
+		{"state = [6] // six\nnext()", "state", `[6]`},
+		{`var a = [7], state = [8]`, "a", `[7]`},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		start, end, err := FindJsonAssignment(s, 0, c.name)
+		if err != nil || string(s[start:end]) != c.exp {
+			t.Errorf("FindJsonAssignment(%s, %s) returns %d, %d, %v", c.s, c.name, start, end, err)
+		}
+	}
+
+	errorList := []struct {
+		s    string
+		name string
+		err  string
+	}{
+		{`state: [1]`, "state", "JSON error at 5: expect '=', got ': '"},
+		{`state === [1]`, "state", "JSON error at 6: expect '=', got '=='"},
+		{`state = init()`, "state", "JSON error at 8: unexpected first char 'i'"},
+		{`state = [1] + [2];`, "state", "JSON error at 12: expect ';', ',' or new line, got '+'"},
+		{`state = {"a": 1}.a`, "state", "JSON error at 16: expect ';', ',' or new line, got '.'"},
+		{`states = [1]`, "state", "JSON error at 12: variable 'state' not found"},
+		{`window["state] = [1]`, "state", "JSON error at 20: variable 'state' not found"},
+	}
+
+	for _, c := range errorList {
+		s := []byte(c.s)
+		start, end, err := FindJsonAssignment(s, 0, c.name)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("FindJsonAssignment(%s, %s) returns %d, %d, %v", c.s, c.name, start, end, err)
+		}
+	}
+}
+
+func ExampleFindJsonAssignment() {
+	s := []byte(`<script>
+		window.__CONFIG__ = {"debug": false};
+		window.__STATE__ = {user: {id: 1, name: 'a'}};
+	</script>`)
+
+	start, end, err := FindJsonAssignment(s, 0, "__STATE__")
+	if err == nil {
+		fmt.Println(string(s[start:end]))
+	}
+
+	// Output:
+	// {user: {id: 1, name: 'a'}}
+}
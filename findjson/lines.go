@@ -0,0 +1,222 @@
+package findjson
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// How lines without any value are handled, blank lines are always skipped.
+type LineMissingPolicy int
+
+const (
+	LineMissingSkip  = LineMissingPolicy(0) // line is skipped
+	LineMissingError = LineMissingPolicy(1) // reading stops, the error is reported by Err
+)
+
+// How lines with more than one value are handled.
+type LineMultiplePolicy int
+
+const (
+	// One value is reported, selected by Options.Selection, the first one by default.
+	LineMultipleSelect = LineMultiplePolicy(0)
+
+	// All values are reported one by one, in order, Options.Selection does not apply.
+	LineMultipleAll = LineMultiplePolicy(1)
+
+	// Reading stops, the error is reported by Err, Options.Selection does not apply.
+	LineMultipleError = LineMultiplePolicy(2)
+)
+
+// Options of reading JSON lines, see LineReader.
+type LineOptions struct {
+	Missing  LineMissingPolicy  // lines without any value
+	Multiple LineMultiplePolicy // lines with more than one value
+}
+
+// Error of line, violating policies of LineOptions, or failure of reading.
+type LineError struct {
+	Line int   // line number, from 1
+	Err  error // *JsonError with offset in line, or error of reader
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Value found in a line.
+type LineMatch struct {
+	Match // offsets are in line, without line terminator
+
+	Line       int   // line number, from 1
+	LineOffset int64 // offset of line in input
+	Index      int   // index of value in line, from 0, always 0 except in LineMultipleAll
+}
+
+// LineReader reads JSON values line by line, e.g. NDJSON and JSON Lines, where lines may have
+// prefixes like timestamps and log levels.
+//
+//	r := NewLineReader(input, JsonValueObject, nil, nil)
+//	for r.Next() {
+//		m := r.Match()
+//		...
+//	}
+//
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+type LineReader struct {
+	reader  *bufio.Reader
+	kind    JsonValueKind
+	lines   *LineOptions
+	options *Options
+
+	line       int   // number of the current line
+	lineOffset int64 // offset of the current line
+	nextOffset int64 // offset of the next line
+	finder     *Finder
+	index      int
+	match      LineMatch
+	err        error
+}
+
+// Create reader of values of kind in lines from r, 0 for JsonValueObject, with line options and
+// scanning options, nil for default.
+func NewLineReader(r io.Reader, kind JsonValueKind, lines *LineOptions, options *Options) *LineReader {
+	if kind == 0 {
+		kind = JsonValueObject
+	}
+
+	if lines == nil {
+		lines = &LineOptions{}
+	}
+
+	multiple := lines.Multiple == LineMultipleAll || lines.Multiple == LineMultipleError
+	if multiple && options != nil && options.Selection != SelectFirst {
+		// the first value is found as Finder does, to be reported or checked with the rest
+		o := *options
+		o.Selection = SelectFirst
+		options = &o
+	}
+
+	reader := &LineReader{
+		reader:  bufio.NewReader(r),
+		kind:    kind,
+		lines:   lines,
+		options: options,
+	}
+
+	return reader
+}
+
+// Read next line, without line terminator, returns false at EOF or on error.
+func (r *LineReader) readLine() ([]byte, bool) {
+	line, err := r.reader.ReadBytes(jsonNewLine)
+	if err != nil && err != io.EOF {
+		r.err = &LineError{Line: r.line + 1, Err: err}
+		return nil, false
+	}
+
+	if len(line) == 0 {
+		return nil, false
+	}
+
+	r.line++
+	r.lineOffset = r.nextOffset
+	r.nextOffset += int64(len(line))
+	line = bytes.TrimSuffix(line, []byte{jsonNewLine})
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	return line, true
+}
+
+// Report value m found in the current line.
+func (r *LineReader) report(m Match) bool {
+	r.match = LineMatch{
+		Match:      m,
+		Line:       r.line,
+		LineOffset: r.lineOffset,
+		Index:      r.index,
+	}
+
+	r.index++
+	return true
+}
+
+// Check line without any value, m is the failure of finding, returns true to skip the line.
+func (r *LineReader) missing(m Match) bool {
+	if r.lines.Missing == LineMissingError {
+		r.err = &LineError{Line: r.line, Err: m.Err}
+		return false
+	}
+
+	return true
+}
+
+// Find next value, returns false if there are no more values, or reading stops on error.
+func (r *LineReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	if r.finder != nil {
+		if r.finder.Next() {
+			return r.report(r.finder.Match())
+		}
+
+		r.finder = nil
+	}
+
+	for {
+		line, ok := r.readLine()
+		if !ok {
+			return false
+		}
+
+		if jumpNextNonWhiteSpace(line, 0) >= len(line) {
+			// blank line
+			continue
+		}
+
+		r.index = 0
+		sc := newJsonScanner(r.options)
+		m := sc.findSelected(line, 0, r.kind)
+		if !m.Found() {
+			if r.missing(m) {
+				continue
+			}
+
+			return false
+		}
+
+		switch r.lines.Multiple {
+		case LineMultipleAll:
+			// m is the first value, the rest are found after it
+			r.finder = newFinderAt(line, m.End, r.kind, sc.fork())
+			return r.report(m)
+
+		case LineMultipleError:
+			f := newFinderAt(line, 0, r.kind, sc.fork())
+			if f.Next() && f.Next() {
+				second := f.Match()
+				e := NewJsonError(second.Start, "more than one value in line")
+				r.err = &LineError{Line: r.line, Err: e}
+				return false
+			}
+
+			return r.report(m)
+		}
+
+		return r.report(m)
+	}
+}
+
+// Get the value found by the last call of Next.
+func (r *LineReader) Match() LineMatch {
+	return r.match
+}
+
+// Get error which stops reading, nil at EOF.
+func (r *LineReader) Err() error {
+	return r.err
+}
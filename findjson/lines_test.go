@@ -0,0 +1,148 @@
+package findjson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testLogLines = "2024-05-01T10:00:00Z INFO {\"id\": 1}\r\n" +
+	"\n" +
+	"2024-05-01T10:00:01Z WARN no payload\n" +
+	"   \n" +
+	"2024-05-01T10:00:02Z INFO {\"id\": 2} {\"id\": 3}\n" +
+	"{\"id\": 4}"
+
+func lineCollect(r *LineReader) []string {
+	got := make([]string, 0)
+	for r.Next() {
+		m := r.Match()
+		got = append(got, fmt.Sprintf("%d:%d:%s", m.Line, m.Index, m.Bytes()))
+	}
+
+	return got
+}
+
+func TestLineReader(t *testing.T) {
+	r := NewLineReader(strings.NewReader(testLogLines), 0, nil, nil)
+	got := lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:{"id": 1}`, `5:0:{"id": 2}`, `6:0:{"id": 4}`})
+	if r.Err() != nil {
+		t.Errorf("Err() returns %v", r.Err())
+	}
+
+	r = NewLineReader(strings.NewReader(testLogLines), JsonValueObject, &LineOptions{Multiple: LineMultipleAll}, nil)
+	got = lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:{"id": 1}`, `5:0:{"id": 2}`, `5:1:{"id": 3}`, `6:0:{"id": 4}`})
+
+	// values are reported in order, selection does not apply
+	input := "1 [2, 3] {\"a\": [4, 5, 6]}\n[{\"b\": 7, \"c\": 8}]"
+	lines := &LineOptions{Multiple: LineMultipleAll}
+	r = NewLineReader(strings.NewReader(input), JsonValueAll, lines, &Options{Selection: SelectLargest, MinItems: 2})
+	got = lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:[2, 3]`, `1:1:[4, 5, 6]`, `2:0:{"b": 7, "c": 8}`})
+
+	// other values are not objects, e.g. numbers in timestamps
+	r = NewLineReader(strings.NewReader(testLogLines), JsonValueAll, nil, &Options{Selection: SelectOutermost})
+	got = lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:{"id": 1}`, `5:0:{"id": 2}`, `6:0:{"id": 4}`})
+}
+
+func TestLineReaderOffsets(t *testing.T) {
+	r := NewLineReader(strings.NewReader(testLogLines), 0, nil, nil)
+	offsets := []int64{0, 79, 125}
+	for i := 0; r.Next(); i++ {
+		m := r.Match()
+		if m.LineOffset != offsets[i] || m.Start != 26 && m.Start != 0 {
+			t.Errorf("value %s is found at %d in line at %d", m.Bytes(), m.Start, m.LineOffset)
+		}
+
+		k := int(m.LineOffset) + m.Start
+		if testLogLines[k:k+m.End-m.Start] != string(m.Bytes()) {
+			t.Errorf("value %s is not at %d in input", m.Bytes(), k)
+		}
+	}
+}
+
+func TestLineReaderPolicies(t *testing.T) {
+	r := NewLineReader(strings.NewReader(testLogLines), 0, &LineOptions{Missing: LineMissingError}, nil)
+	got := lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:{"id": 1}`})
+
+	e, ok := r.Err().(*LineError)
+	if !ok || e.Line != 3 || e.Error() != "line 3: JSON error at 36: no JSON string found in object" {
+		t.Errorf("Err() returns %v", r.Err())
+	}
+
+	if r.Next() {
+		t.Errorf("Next() returns true after error")
+	}
+
+	r = NewLineReader(strings.NewReader(testLogLines), 0, &LineOptions{Multiple: LineMultipleError}, nil)
+	got = lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:{"id": 1}`})
+
+	e, ok = r.Err().(*LineError)
+	if !ok || e.Line != 5 || e.Err.(*JsonError).Offset != 36 {
+		t.Errorf("Err() returns %v", r.Err())
+	}
+
+	// the second value is checked after the first one, selection does not apply
+	lines := &LineOptions{Multiple: LineMultipleError}
+	r = NewLineReader(strings.NewReader("[1, 2]\n1 [2, 3]\n"), JsonValueAll, lines, &Options{Selection: SelectLargest})
+	got = lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:[1, 2]`})
+	if e, ok = r.Err().(*LineError); !ok || e.Line != 2 || e.Err.(*JsonError).Offset != 2 {
+		t.Errorf("Err() returns %v", r.Err())
+	}
+
+	// failed candidates are reported
+	r = NewLineReader(strings.NewReader("{\"a\": 1}\n{\"b\": }\n"), 0, &LineOptions{Missing: LineMissingError}, nil)
+	got = lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:{"a": 1}`})
+	if e, ok = r.Err().(*LineError); !ok || e.Line != 2 || e.Err.(*JsonError).Offset != 6 {
+		t.Errorf("Err() returns %v", r.Err())
+	}
+}
+
+type failingReader struct {
+	data string
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errors.New("connection reset")
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestLineReaderFailure(t *testing.T) {
+	r := NewLineReader(&failingReader{data: "{\"a\": 1}\n{\"b\": 2"}, 0, nil, nil)
+	got := lineCollect(r)
+	checkFinderResult(t, got, []string{`1:0:{"a": 1}`})
+
+	if r.Err() == nil || r.Err().Error() != "line 2: connection reset" {
+		t.Errorf("Err() returns %v", r.Err())
+	}
+}
+
+func ExampleLineReader() {
+	input := strings.NewReader("" +
+		"[10:00:00] GET /a {\"status\": 200}\n" +
+		"[10:00:01] GET /b\n" +
+		"[10:00:02] GET /c {\"status\": 404}\n")
+
+	r := NewLineReader(input, JsonValueObject, nil, nil)
+	for r.Next() {
+		m := r.Match()
+		fmt.Printf("line %d [%d, %d): %s\n", m.Line, m.Start, m.End, m.Bytes())
+	}
+
+	// Output:
+	// line 1 [18, 33): {"status": 200}
+	// line 3 [18, 33): {"status": 404}
+}
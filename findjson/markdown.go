@@ -0,0 +1,177 @@
+package findjson
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Grammar styles of fenced code block languages.
+var markdownLanguageStyles = map[string]Style{
+	"json":  NormativeStyle,
+	"jsonc": StyleComments | StyleTrailingComma,
+	"json5": StyleComments | StyleTrailingComma | StyleSingleQuotes | StyleUnquotedKeys |
+		StyleSpecialNumbers,
+}
+
+// Get grammar style of fenced code block language, e.g. NormativeStyle for "json", returns
+// false if language is not a JSON dialect.
+func MarkdownLanguageStyle(language string) (Style, bool) {
+	style, found := markdownLanguageStyles[strings.ToLower(language)]
+	return style, found
+}
+
+// Value found in Markdown document.
+type MarkdownMatch struct {
+	Match // offsets are in document
+
+	Fenced   bool   // found in a fenced code block
+	Language string // language of fenced code block, lower-cased, empty in raw text
+}
+
+// Fenced code block, body is s[bodyStart:bodyEnd].
+type markdownFence struct {
+	language  string
+	bodyStart int
+	bodyEnd   int
+}
+
+// Get end of line at s[i], after the new line.
+func lineEnd(s []byte, i int) int {
+	if k := bytes.IndexByte(s[i:], jsonNewLine); k >= 0 {
+		return i + k + 1
+	}
+
+	return len(s)
+}
+
+// Check whether line is a fence, returns fence char, length of fence and the info string.
+func parseMarkdownFence(line []byte) (byte, int, []byte) {
+	// indented up to 3 spaces, 4 spaces or a tab make an indented code block
+	indent := 0
+	for indent < len(line) && indent < 4 && line[indent] == ' ' {
+		indent++
+	}
+
+	if indent > 3 {
+		return 0, 0, nil
+	}
+
+	line = line[indent:]
+	if len(line) < 3 || (line[0] != '`' && line[0] != '~') {
+		return 0, 0, nil
+	}
+
+	c := line[0]
+	n := 0
+	for n < len(line) && line[n] == c {
+		n++
+	}
+
+	info := bytes.TrimSpace(line[n:])
+	if n < 3 || (c == '`' && bytes.IndexByte(info, '`') >= 0) {
+		return 0, 0, nil
+	}
+
+	return c, n, info
+}
+
+// Get language of fence from info string, e.g. "json" of "json title=a.json" and "{.json}".
+func markdownFenceLanguage(info []byte) string {
+	if len(info) > 0 && info[0] == '{' {
+		// attributes, e.g. {.json}
+		info = bytes.TrimLeft(info[1:], ". \t")
+	}
+
+	if k := bytes.IndexAny(info, " \t{}"); k >= 0 {
+		info = info[:k]
+	}
+
+	return strings.ToLower(string(info))
+}
+
+// Find fenced code blocks in Markdown document, an unclosed fence goes to EOF.
+func findMarkdownFences(s []byte) []markdownFence {
+	fences := make([]markdownFence, 0)
+	l := len(s)
+	i := 0
+	for i < l {
+		next := lineEnd(s, i)
+		c, n, info := parseMarkdownFence(s[i:next])
+		if n == 0 {
+			i = next
+			continue
+		}
+
+		fence := markdownFence{
+			language:  markdownFenceLanguage(info),
+			bodyStart: next,
+			bodyEnd:   l,
+		}
+
+		i = next
+		for i < l {
+			next = lineEnd(s, i)
+			c2, n2, info2 := parseMarkdownFence(s[i:next])
+			if c2 == c && n2 >= n && len(info2) == 0 {
+				fence.bodyEnd = i
+				i = next
+				break
+			}
+
+			i = next
+		}
+
+		fences = append(fences, fence)
+	}
+
+	return fences
+}
+
+// Find all values in s[i:end] with options.
+func findAllInRange(s []byte, i int, end int, kind JsonValueKind, options *Options) []Match {
+	result := make([]Match, 0)
+	f := NewFinder(s[:end], kind, options)
+	f.Seek(i)
+	for f.Next() {
+		result = append(result, f.Match())
+	}
+
+	return result
+}
+
+// Find JSON values in Markdown document s, e.g. answers of LLMs and README files, with options,
+// nil for default.
+//
+// Values in fenced code blocks tagged json, jsonc and json5 are preferred, each block is searched
+// in the style of its language, see MarkdownLanguageStyle, other fields of options are kept.
+// If no value is found in such blocks, including there is no such block, the whole document is
+// searched in options.Style as raw text.
+func FindJsonInMarkdown(s []byte, kind JsonValueKind, options *Options) []MarkdownMatch {
+	if options == nil {
+		options = &Options{}
+	}
+
+	result := make([]MarkdownMatch, 0)
+	for _, fence := range findMarkdownFences(s) {
+		style, ok := MarkdownLanguageStyle(fence.language)
+		if !ok {
+			continue
+		}
+
+		fenceOptions := *options
+		fenceOptions.Style = style
+		for _, m := range findAllInRange(s, fence.bodyStart, fence.bodyEnd, kind, &fenceOptions) {
+			result = append(result, MarkdownMatch{Match: m, Fenced: true, Language: fence.language})
+		}
+	}
+
+	if len(result) > 0 {
+		return result
+	}
+
+	for _, m := range findAllInRange(s, 0, len(s), kind, options) {
+		result = append(result, MarkdownMatch{Match: m})
+	}
+
+	return result
+}
@@ -0,0 +1,119 @@
+package findjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func markdownCollect(s []byte, kind JsonValueKind, options *Options) ([]string, []MarkdownMatch) {
+	got := make([]string, 0)
+	ms := FindJsonInMarkdown(s, kind, options)
+	for _, m := range ms {
+		got = append(got, string(m.Bytes()))
+	}
+
+	return got, ms
+}
+
+func TestFindJsonInMarkdownFences(t *testing.T) {
+	s := []byte("Here is the result {\"prose\": 0}:\n" +
+		"```json\n" +
+		"{\"a\": 1}\n" +
+		"```\n" +
+		"```python\n" +
+		"x = {\"b\": 2}\n" +
+		"```\n" +
+		"  ~~~~ JSONC title=config.jsonc\n" +
+		"// comment\n" +
+		"{\"c\": [3,],}\n" +
+		"~~~ not closing\n" +
+		"```\n" +
+		"~~~~~\n" +
+		"```{.json5}\n" +
+		"Two values: {d: 'e'} and {f: .5}\n")
+
+	got, ms := markdownCollect(s, JsonValueObject, nil)
+	checkFinderResult(t, got, []string{`{"a": 1}`, `{"c": [3,],}`, `{d: 'e'}`, `{f: .5}`})
+
+	languages := []string{"json", "jsonc", "json5", "json5"}
+	for i, m := range ms {
+		if !m.Fenced || m.Language != languages[i] {
+			t.Errorf("match[%d] is in fence %v of '%s'", i, m.Fenced, m.Language)
+		}
+	}
+
+	if ms[0].Style != NormativeStyle || !ms[2].Style.Has(StyleUnquotedKeys) {
+		t.Errorf("styles of matches are %s and %s", ms[0].Style, ms[2].Style)
+	}
+
+	// the fence is closed, the second value is not in any json fence
+	s = []byte("```json\n[1]\n````\n[2]\n")
+	got, _ = markdownCollect(s, JsonValueArray, nil)
+	checkFinderResult(t, got, []string{`[1]`})
+
+	// ``` with backtick in info string is not a fence
+	s = []byte("```json `x`\n[1]\n")
+	got, ms = markdownCollect(s, JsonValueArray, nil)
+	checkFinderResult(t, got, []string{`[1]`})
+	if ms[0].Fenced {
+		t.Errorf("value is found in fence")
+	}
+
+	// fences are indented up to 3 spaces, otherwise they are in indented code blocks
+	s = []byte("    ```json\n[1]\n    ```\n   ```json\n[2]\n   ```\n\t```json\n[3]\n")
+	got, ms = markdownCollect(s, JsonValueArray, nil)
+	checkFinderResult(t, got, []string{`[2]`})
+	if !ms[0].Fenced {
+		t.Errorf("value is not found in fence")
+	}
+}
+
+func TestFindJsonInMarkdownFallback(t *testing.T) {
+	inputs := []string{
+		"The answer is {'a': 1,} as you see.",
+		"```json\nno value here\n```\nThe answer is {'a': 1,} as you see.",
+		"```js\nThe answer is {'a': 1,} as you see.\n```",
+	}
+
+	for _, v := range inputs {
+		got, ms := markdownCollect([]byte(v), JsonValueObject, &Options{Style: PythonStyle})
+		checkFinderResult(t, got, []string{`{'a': 1,}`})
+		if ms[0].Fenced || ms[0].Language != "" || ms[0].Style != PythonStyle {
+			t.Errorf("value in %q is found in fence '%s'", v, ms[0].Language)
+		}
+	}
+
+	got, _ := markdownCollect([]byte("nothing"), JsonValueAll, nil)
+	checkFinderResult(t, got, []string{})
+}
+
+func TestMarkdownLanguageStyle(t *testing.T) {
+	if style, ok := MarkdownLanguageStyle("JSON"); !ok || style != NormativeStyle {
+		t.Errorf("MarkdownLanguageStyle(JSON) returns %s, %v", style, ok)
+	}
+
+	if style, ok := MarkdownLanguageStyle("jsonc"); !ok || style.Has(StyleSingleQuotes) {
+		t.Errorf("MarkdownLanguageStyle(jsonc) returns %s, %v", style, ok)
+	}
+
+	if _, ok := MarkdownLanguageStyle("yaml"); ok {
+		t.Errorf("MarkdownLanguageStyle(yaml) returns true")
+	}
+}
+
+func ExampleFindJsonInMarkdown() {
+	s := []byte("Sure! Here is the config:\n\n" +
+		"```jsonc\n" +
+		"{\n" +
+		"  // enable debug\n" +
+		"  \"debug\": true,\n" +
+		"}\n" +
+		"```\n")
+
+	for _, m := range FindJsonInMarkdown(s, JsonValueObject, nil) {
+		fmt.Printf("%s %q\n", m.Language, m.Bytes())
+	}
+
+	// Output:
+	// jsonc "{\n  // enable debug\n  \"debug\": true,\n}"
+}
@@ -0,0 +1,92 @@
+package findjson
+
+// Result of finding a JSON value, on success or failure.
+type Match struct {
+	Start int           // start of value, or of the failed candidate
+	End   int           // end of value, exclusive, equals Start on failure
+	Kind  JsonValueKind // kind of value, 0 if no candidate found
+	Style Style         // grammar style used
+	Depth int           // max nesting depth, 0 for scalars, 1 for [] and {}
+	Items int           // members of object or elements of array, 0 for scalars
+
+	// How far scanning got, End on success. On failure, the partial match is
+	// s[Start:PartialEnd], and searching may resume from PartialEnd.
+	PartialEnd int
+
+	// Error on failure, nil on success.
+	Err *JsonError
+
+	buffer []byte
+}
+
+// Check whether a value is found.
+func (m Match) Found() bool {
+	return m.Err == nil
+}
+
+// Get bytes of value found, nil on failure.
+func (m Match) Bytes() []byte {
+	if m.Err != nil {
+		return nil
+	}
+
+	return m.buffer[m.Start:m.End]
+}
+
+// Get offset to resume searching from, after this match.
+func (m Match) Next() int {
+	return m.PartialEnd
+}
+
+// Convert error from scanners to *JsonError, errors from provided scanners may be of any type.
+func toJsonError(err error, offset int) *JsonError {
+	if err == nil {
+		return nil
+	}
+
+	if e, ok := err.(*JsonError); ok {
+		return e
+	}
+
+	return NewJsonError(offset, "%s", err)
+}
+
+func (sc *jsonScanner) findMatch(s []byte, i int, kind JsonValueKind) Match {
+	sc.kind = 0
+	sc.maxDepth = 0
+	sc.items = 0
+	start, end, err := sc.find(s, i, kind)
+
+	m := Match{
+		Start:      start,
+		End:        end,
+		Kind:       sc.kind,
+		Style:      sc.options.Style,
+		Depth:      sc.maxDepth,
+		Items:      sc.items,
+		PartialEnd: end,
+		Err:        toJsonError(err, end),
+		buffer:     s,
+	}
+
+	if err != nil {
+		m.End = start
+	}
+
+	return m
+}
+
+// Find JSON value in mixed content, start from offset i, with options specified.
+func FindJsonMatchWithOptions(s []byte, i int, kind JsonValueKind, options *Options) Match {
+	return newJsonScanner(options).findSelected(s, i, kind)
+}
+
+// Find JSON value in mixed content, start from offset i, with style specified.
+func FindJsonMatchWithStyle(s []byte, i int, kind JsonValueKind, style Style) Match {
+	return FindJsonMatchWithOptions(s, i, kind, &Options{Style: style})
+}
+
+// Find JSON value in mixed content, start from offset i.
+func FindJsonMatch(s []byte, i int, kind JsonValueKind) Match {
+	return FindJsonMatchWithStyle(s, i, kind, NormativeStyle)
+}
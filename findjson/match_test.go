@@ -0,0 +1,100 @@
+package findjson
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFindJsonMatch(t *testing.T) {
+	//           0         1         2         3
+	//           0123456789012345678901234567890123456
+	s := []byte(`a = [1, [2, {"b": []}]]; c = "d";`)
+
+	m := FindJsonMatch(s, 0, JsonValueAll)
+	if !m.Found() || m.Start != 4 || m.End != 23 || m.PartialEnd != 23 {
+		t.Fatalf("FindJsonMatch() returns %+v", m)
+	}
+
+	if m.Kind != JsonValueArray || m.Depth != 4 || m.Items != 2 || m.Style != NormativeStyle {
+		t.Errorf("FindJsonMatch() returns kind=%s depth=%d items=%d style=%s", m.Kind, m.Depth, m.Items, m.Style)
+	}
+
+	if string(m.Bytes()) != `[1, [2, {"b": []}]]` {
+		t.Errorf("FindJsonMatch() returns bytes %s", m.Bytes())
+	}
+
+	m = FindJsonMatch(s, m.Next(), JsonValueAll)
+	if !m.Found() || m.Kind != JsonValueString || m.Depth != 0 || string(m.Bytes()) != `"d"` {
+		t.Errorf("FindJsonMatch() returns %+v", m)
+	}
+
+	m = FindJsonMatch(s, m.Next(), JsonValueAll)
+	if m.Found() || m.Kind != 0 || m.Start != 32 || m.End != 32 || m.PartialEnd != 33 {
+		t.Errorf("FindJsonMatch() returns %+v", m)
+	}
+
+	if m.Err == nil || m.Err.Error() != "JSON error at 33: no JSON string found in null|boolean|number|string|array|object" {
+		t.Errorf("FindJsonMatch() returns error %v", m.Err)
+	}
+}
+
+func TestFindJsonMatchFailure(t *testing.T) {
+	//           0         1         2
+	//           012345678901234567890123
+	s := []byte(`x = {"a": [1, 2,], "b"}`)
+
+	m := FindJsonMatch(s, 0, JsonValueObject)
+	if m.Found() || m.Start != 4 || m.End != 4 || m.PartialEnd != 16 || m.Bytes() != nil {
+		t.Errorf("FindJsonMatch() returns %+v", m)
+	}
+
+	if m.Kind != JsonValueObject || m.Err == nil || m.Err.Offset != 16 {
+		t.Errorf("FindJsonMatch() returns kind=%s error %v", m.Kind, m.Err)
+	}
+
+	m = FindJsonMatchWithStyle(s, 0, JsonValueObject, JavaScriptLiteralStyle)
+	if m.Found() || m.PartialEnd != 22 || m.Style != JavaScriptLiteralStyle {
+		t.Errorf("FindJsonMatchWithStyle() returns %+v", m)
+	}
+
+	m = FindJsonMatchWithStyle(s, 0, JsonValueArray, JavaScriptLiteralStyle)
+	if !m.Found() || string(m.Bytes()) != "[1, 2,]" || m.Depth != 1 {
+		t.Errorf("FindJsonMatchWithStyle() returns %+v", m)
+	}
+}
+
+func TestFindJsonMatchWithProvider(t *testing.T) {
+	options := &Options{
+		Provider: func(kind JsonValueKind) JsonTokenScanner {
+			if kind == JsonValueString {
+				return scanBacktickString
+			}
+
+			return nil
+		},
+	}
+
+	s := []byte("a = `template`;")
+	m := FindJsonMatchWithOptions(s, 0, JsonValueAll, options)
+	if !m.Found() || m.Kind != JsonValueString || string(m.Bytes()) != "`template`" {
+		t.Errorf("FindJsonMatchWithOptions() returns %+v", m)
+	}
+}
+
+func ExampleFindJsonMatch() {
+	s := []byte(`a = [1, 2, [3]]; b = {"c": 4}`)
+
+	i := 0
+	for i < len(s) {
+		m := FindJsonMatch(s, i, JsonValueArray|JsonValueObject)
+		if m.Found() {
+			fmt.Println(m.Kind, m.Depth, string(m.Bytes()))
+		}
+
+		i = m.Next()
+	}
+
+	// Output:
+	// array 2 [1, 2, [3]]
+	// object 1 {"c": 4}
+}
@@ -0,0 +1,342 @@
+package findjson
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// Form of number, tells which non-standard form is used, see StyleSpecialNumbers.
+type NumberForm int
+
+const (
+	NumberFormNormative  = NumberForm(0) // number in RFC 8259
+	NumberFormNaN        = NumberForm(1) // NaN
+	NumberFormInfinity   = NumberForm(2) // Infinity or -Infinity
+	NumberFormHex        = NumberForm(3) // hexadecimal integer, e.g. 0x1F, -0xff
+	NumberFormLeadingDot = NumberForm(4) // float without leading digit, e.g. .5, -.5e3
+)
+
+var (
+	jsonLiteralNaN      = []byte("NaN")
+	jsonLiteralInfinity = []byte("Infinity")
+)
+
+func (f NumberForm) String() string {
+	switch f {
+	case NumberFormNormative:
+		return "normative"
+
+	case NumberFormNaN:
+		return "NaN"
+
+	case NumberFormInfinity:
+		return "Infinity"
+
+	case NumberFormHex:
+		return "hex"
+
+	case NumberFormLeadingDot:
+		return "leading-dot"
+	}
+
+	return "unknown"
+}
+
+// Get form of number s[start:end], which is found as JsonValueNumber.
+func GetNumberForm(s []byte, start int, end int) NumberForm {
+	j := start
+	if j < end && s[j] == jsonSignNegative {
+		j++
+	}
+
+	if j >= end {
+		return NumberFormNormative
+	}
+
+	switch s[j] {
+	case 'N':
+		return NumberFormNaN
+
+	case 'I':
+		return NumberFormInfinity
+
+	case jsonPeriod:
+		return NumberFormLeadingDot
+
+	case jsonDigitZero:
+		if j+1 < end && (s[j+1] == 'x' || s[j+1] == 'X') {
+			return NumberFormHex
+		}
+	}
+
+	return NumberFormNormative
+}
+
+// Scan number in relaxed grammar of StyleSpecialNumbers, numbers in RFC 8259 and the forms in
+// NumberForm are accepted.
+func scanSpecialNumber(s []byte, i int) (int, int, error) {
+	var err error
+	l := len(s)
+	j := i
+
+	if s[j] == 'N' {
+		m, n := bufferStartsWith(s, j, jsonLiteralNaN)
+		if !m {
+			v := bufferFindSample(s, j, 3)
+			err = NewJsonError(j, "expect NaN, got '%s'", v)
+		}
+
+		return i, j + n, err
+	}
+
+	if s[j] == jsonSignNegative {
+		j++
+	}
+
+	if j >= l {
+		v := bufferFindSample(s, j, 1)
+		err = NewJsonError(j, "expect digit, got '%s'", v)
+		return i, j, err
+	}
+
+	switch c := s[j]; {
+	case c == 'I':
+		m, n := bufferStartsWith(s, j, jsonLiteralInfinity)
+		if !m {
+			v := bufferFindSample(s, j, 8)
+			err = NewJsonError(j, "expect Infinity, got '%s'", v)
+		}
+
+		return i, j + n, err
+
+	case c == jsonPeriod:
+		_, j, err = scanFractionAndExponent(s, j)
+		return i, j, err
+
+	case c == jsonDigitZero && j+1 < l && (s[j+1] == 'x' || s[j+1] == 'X'):
+		_, j, err = scanHexDigits(s, j+2)
+		return i, j, err
+	}
+
+	return scanJsonNumber(s, i)
+}
+
+// Max magnitude of decimal exponent for exact conversions, BigInt and BigRat, to avoid
+// computing huge powers of 10 on untrusted input.
+const maxExactExponent = 1 << 14
+
+// Max magnitude of decimal exponent for BigFloat, far beyond float64 but small enough for
+// big.ParseFloat to return quickly on untrusted input.
+const maxFloatExponent = 1 << 24
+
+// Number parsed from JSON number in RFC 8259, parts are slices of the buffer.
+type Number struct {
+	Offset   int    // start of number in buffer
+	Text     []byte // whole number
+	Negative bool   // minus sign found
+	Integer  []byte // digits of integer part
+	Fraction []byte // digits of fraction part, nil if absent
+	Exponent []byte // exponent part with optional sign, e.g. "+10", nil if absent
+}
+
+// Parse JSON number s[start:end], the span returned by number scanner in NormativeStyle.
+func ParseNumber(s []byte, start int, end int) (Number, error) {
+	var n Number
+	if start < 0 || end > len(s) || start >= end {
+		err := NewJsonError(start, "invalid number span [%d, %d)", start, end)
+		return n, err
+	}
+
+	b := s[:end]
+	_, j, err := scanJsonNumber(b, start)
+	if err != nil {
+		return n, err
+
+	} else if j < end {
+		v := bufferFindSample(b, j, 1)
+		err := NewJsonError(j, "expect end of number, got '%s'", v)
+		return n, err
+	}
+
+	n.Offset = start
+	n.Text = b[start:end]
+	j = start
+	if b[j] == jsonSignNegative {
+		n.Negative = true
+		j++
+	}
+
+	_, k, _ := scanDigits(b, j)
+	n.Integer = b[j:k]
+	j = k
+
+	if j < end && b[j] == jsonPeriod {
+		_, k, _ = scanDigits(b, j+1)
+		n.Fraction = b[j+1 : k]
+		j = k
+	}
+
+	if j < end {
+		// exponent, 'e' or 'E'
+		n.Exponent = b[j+1 : end]
+	}
+
+	return n, nil
+}
+
+// Check whether value of number is an integer, e.g. 1, 1.0, 1.5e1.
+func (n Number) IsInteger() bool {
+	digits := len(n.Integer) + len(n.Fraction)
+	zeros := 0 // trailing zeros of all digits
+	for zeros < len(n.Fraction) && n.Fraction[len(n.Fraction)-zeros-1] == jsonDigitZero {
+		zeros++
+	}
+
+	if zeros == len(n.Fraction) {
+		for zeros < digits && n.Integer[digits-zeros-1] == jsonDigitZero {
+			zeros++
+		}
+	}
+
+	if zeros == digits {
+		// zero
+		return true
+	}
+
+	exp, err := n.exponent()
+	if err != nil {
+		// out of int64 range, integer if exponent is positive
+		return n.Exponent[0] != jsonSignNegative
+	}
+
+	return exp-int64(len(n.Fraction))+int64(zeros) >= 0
+}
+
+// Get value of exponent part, 0 if absent.
+func (n Number) exponent() (int64, error) {
+	if len(n.Exponent) == 0 {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(string(n.Exponent), 10, 64)
+}
+
+// Get exact value as int64.
+func (n Number) Int64() (int64, error) {
+	if len(n.Fraction) == 0 && len(n.Exponent) == 0 && len(n.Integer) <= 18 {
+		// fast path, up to 18 digits always fit in int64
+		return strconv.ParseInt(string(n.Text), 10, 64)
+	}
+
+	v, err := n.BigInt()
+	if err != nil {
+		return 0, err
+	}
+
+	if !v.IsInt64() {
+		return 0, n.newOutOfRangeError("int64")
+	}
+
+	return v.Int64(), nil
+}
+
+// Get exact value as uint64.
+func (n Number) Uint64() (uint64, error) {
+	if !n.Negative && len(n.Fraction) == 0 && len(n.Exponent) == 0 && len(n.Integer) <= 19 {
+		// fast path, up to 19 digits always fit in uint64
+		return strconv.ParseUint(string(n.Text), 10, 64)
+	}
+
+	v, err := n.BigInt()
+	if err != nil {
+		return 0, err
+	}
+
+	if !v.IsUint64() {
+		return 0, n.newOutOfRangeError("uint64")
+	}
+
+	return v.Uint64(), nil
+}
+
+// Get the nearest float64 value, error is returned on overflow, with +Inf or -Inf.
+func (n Number) Float64() (float64, error) {
+	f, err := strconv.ParseFloat(string(n.Text), 64)
+	if err != nil {
+		return f, n.newOutOfRangeError("float64")
+	}
+
+	return f, nil
+}
+
+// Get exact value as big.Int, error is returned if value is not an integer.
+func (n Number) BigInt() (*big.Int, error) {
+	if !n.IsInteger() {
+		err := NewJsonErrorWithCode(JsonErrorNotInteger, n.Offset, "number %s is not an integer", n.Text)
+		return nil, err
+	}
+
+	r, err := n.BigRat()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Num(), nil
+}
+
+// Get exact value as big.Rat.
+func (n Number) BigRat() (*big.Rat, error) {
+	exp, err := n.exponent()
+	if err != nil || exp > maxExactExponent || exp < -maxExactExponent {
+		return nil, n.newOutOfRangeError("exact value")
+	}
+
+	digits := make([]byte, 0, 1+len(n.Integer)+len(n.Fraction))
+	if n.Negative {
+		digits = append(digits, jsonSignNegative)
+	}
+
+	digits = append(digits, n.Integer...)
+	digits = append(digits, n.Fraction...)
+
+	v, _ := new(big.Int).SetString(string(digits), 10)
+	r := new(big.Rat).SetInt(v)
+
+	scale := exp - int64(len(n.Fraction))
+	if scale != 0 {
+		abs := scale
+		if abs < 0 {
+			abs = -abs
+		}
+
+		p := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs), nil)
+		if scale > 0 {
+			r.Mul(r, new(big.Rat).SetInt(p))
+		} else {
+			r.Quo(r, new(big.Rat).SetInt(p))
+		}
+	}
+
+	return r, nil
+}
+
+// Get value as big.Float, with precision enough to hold all decimal digits.
+func (n Number) BigFloat() (*big.Float, error) {
+	exp, err := n.exponent()
+	if err != nil || exp > maxFloatExponent || exp < -maxFloatExponent {
+		return nil, n.newOutOfRangeError("big.Float")
+	}
+
+	prec := uint(len(n.Integer)+len(n.Fraction))*4 + 64
+	f, _, err := big.ParseFloat(string(n.Text), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, n.newOutOfRangeError("big.Float")
+	}
+
+	return f, nil
+}
+
+func (n Number) newOutOfRangeError(target string) *JsonError {
+	return NewJsonErrorWithCode(JsonErrorNumberOutOfRange, n.Offset,
+		"number %s is out of range of %s", n.Text, target)
+}
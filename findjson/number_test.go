@@ -0,0 +1,335 @@
+package findjson
+
+import (
+	"testing"
+)
+
+func TestScanSpecialNumberSuccess(t *testing.T) {
+	caseList := scannerCorrectCases{
+		"0",
+		"-1234.5678E+90",
+		"NaN",
+		"Infinity",
+		"-Infinity",
+		"0x1F",
+		"0XdeadBEEF",
+		"-0xff",
+		".5",
+		"-.5",
+		".5e3",
+	}
+
+	caseList.On(t, scanSpecialNumber)
+}
+
+func TestScanSpecialNumberFailure(t *testing.T) {
+	type failureCase struct {
+		s   string
+		end int
+		msg string
+	}
+
+	caseList := []failureCase{
+		{"Nah", 2, "JSON error at 0: expect NaN, got 'Nah'"},
+		{"-NaN", 1, "JSON error at 1: expect digit, got 'N'"},
+		{"Infinite", 7, "JSON error at 0: expect Infinity, got 'Infinite'"},
+		{"-Inf", 4, "JSON error at 1: expect Infinity, got 'Inf'"},
+		{"0x", 2, "JSON error at 2: expect hex digit, got 'EOF'"},
+		{"0xg", 2, "JSON error at 2: expect hex digit, got 'g'"},
+		{".", 1, "JSON error at 1: expect digit, got 'EOF'"},
+		{".e5", 1, "JSON error at 1: expect digit, got 'e'"},
+		{"-", 1, "JSON error at 1: expect digit, got 'EOF'"},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		start, end, err := scanSpecialNumber(s, 0)
+		if err == nil {
+			t.Errorf("scanSpecialNumber(%s) returns %d, %d, nil", c.s, start, end)
+			continue
+		}
+
+		if err.Error() != c.msg || start != 0 || end != c.end {
+			t.Errorf("scanSpecialNumber(%s) returns %d, %d, %s", c.s, start, end, err)
+		}
+	}
+}
+
+func TestGetNumberForm(t *testing.T) {
+	type formCase struct {
+		s    string
+		form NumberForm
+	}
+
+	caseList := []formCase{
+		{"0", NumberFormNormative},
+		{"-0.5e3", NumberFormNormative},
+		{"NaN", NumberFormNaN},
+		{"Infinity", NumberFormInfinity},
+		{"-Infinity", NumberFormInfinity},
+		{"0x1F", NumberFormHex},
+		{"-0Xff", NumberFormHex},
+		{".5", NumberFormLeadingDot},
+		{"-.5", NumberFormLeadingDot},
+		{"-", NumberFormNormative},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		if form := GetNumberForm(s, 0, len(s)); form != c.form {
+			t.Errorf("GetNumberForm(%s) returns %s, expected %s", c.s, form, c.form)
+		}
+	}
+}
+
+func TestNumberFormString(t *testing.T) {
+	if v := NumberFormHex.String(); v != "hex" {
+		t.Errorf("NumberFormHex.String() returns '%s'", v)
+	}
+
+	if v := NumberForm(-1).String(); v != "unknown" {
+		t.Errorf("NumberForm(-1).String() returns '%s'", v)
+	}
+}
+
+func TestFindSpecialNumbers(t *testing.T) {
+	s := []byte(`{"a": NaN, "b": [-Infinity, 0x1F, .5], "c": None}`)
+	style := JavaScriptLiteralStyle | StyleSpecialNumbers | StylePythonLiterals
+
+	got := make([]string, 0)
+	i := 0
+	for i < len(s) {
+		start, end, err := FindJsonWithStyle(s, i, JsonValueNumber|JsonValueNull, style)
+		if err == nil {
+			got = append(got, string(s[start:end]))
+		}
+
+		i = end
+	}
+
+	exp := []string{"NaN", "-Infinity", "0x1F", ".5", "None"}
+	if len(got) != len(exp) {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	for i, v := range exp {
+		if got[i] != v {
+			t.Errorf("exp[%d](%s) != got[%d](%s)", i, v, i, got[i])
+		}
+	}
+
+	start, end, err := FindJsonWithStyle(s, 0, JsonValueObject, style)
+	if err != nil || start != 0 || end != len(s) {
+		t.Errorf("FindJsonWithStyle(s, 0) returns %d, %d, %v", start, end, err)
+	}
+
+	start, end, err = FindJsonWithStyle(s, 0, JsonValueObject, JavaScriptLiteralStyle)
+	if err == nil {
+		t.Errorf("FindJsonWithStyle(s, 0) returns %d, %d, nil", start, end)
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	//           0         1         2
+	//           012345678901234567890123
+	s := []byte(`{"n": -12.50e+3, "m": 7}`)
+	//                 |<------->|
+	n, err := ParseNumber(s, 6, 15)
+	if err != nil {
+		t.Fatalf("ParseNumber(s, 6, 15) returns error: %s", err)
+	}
+
+	if !n.Negative || string(n.Integer) != "12" || string(n.Fraction) != "50" ||
+		string(n.Exponent) != "+3" || string(n.Text) != "-12.50e+3" || n.Offset != 6 {
+		t.Errorf("ParseNumber(s, 6, 15) returns %+v", n)
+	}
+
+	if !n.IsInteger() {
+		t.Errorf("%s is not integer", n.Text)
+	}
+
+	if v, err := n.Int64(); err != nil || v != -12500 {
+		t.Errorf("%s.Int64() returns %d, %v", n.Text, v, err)
+	}
+
+	if v, err := n.Uint64(); err == nil || err.(*JsonError).Code != JsonErrorNumberOutOfRange {
+		t.Errorf("%s.Uint64() returns %d, %v", n.Text, v, err)
+	}
+
+	if v, err := n.Float64(); err != nil || v != -12500 {
+		t.Errorf("%s.Float64() returns %f, %v", n.Text, v, err)
+	}
+}
+
+func TestParseNumberFailure(t *testing.T) {
+	type failureCase struct {
+		s     string
+		start int
+		end   int
+		msg   string
+	}
+
+	caseList := []failureCase{
+		{"123abc", 0, 6, "JSON error at 3: expect end of number, got 'a'"},
+		{"1.e5", 0, 4, "JSON error at 2: expect digit, got 'e'"},
+		{"12345", 0, 3, ""},
+		{"abc", 0, 3, "JSON error at 0: expect digit or '-', got 'a'"},
+		{"123", 2, 1, "JSON error at 2: invalid number span [2, 1)"},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		n, err := ParseNumber(s, c.start, c.end)
+		if c.msg == "" {
+			if err != nil || string(n.Text) != c.s[c.start:c.end] {
+				t.Errorf("ParseNumber(%s, %d, %d) returns %+v, %v", c.s, c.start, c.end, n, err)
+			}
+
+			continue
+		}
+
+		if err == nil || err.Error() != c.msg {
+			t.Errorf("ParseNumber(%s, %d, %d) returns %+v, %v", c.s, c.start, c.end, n, err)
+		}
+	}
+}
+
+func parseNumberForTest(t *testing.T, v string) Number {
+	s := []byte(v)
+	n, err := ParseNumber(s, 0, len(s))
+	if err != nil {
+		t.Fatalf("ParseNumber(%s) returns error: %s", v, err)
+	}
+
+	return n
+}
+
+func TestNumberIsInteger(t *testing.T) {
+	integers := []string{
+		"0", "-0", "0.000", "0e-99999999999999999999", "1", "-12", "1.0", "1.50e1",
+		"1200e-2", "1e99999999999999999999", "100000000000000000000000000000",
+	}
+
+	for _, v := range integers {
+		if n := parseNumberForTest(t, v); !n.IsInteger() {
+			t.Errorf("%s is not integer", v)
+		}
+	}
+
+	fractions := []string{
+		"0.5", "-1.25", "1.55e1", "1201e-2", "1e-99999999999999999999",
+	}
+
+	for _, v := range fractions {
+		if n := parseNumberForTest(t, v); n.IsInteger() {
+			t.Errorf("%s is integer", v)
+		}
+	}
+}
+
+func TestNumberIntegerConversion(t *testing.T) {
+	{
+		n := parseNumberForTest(t, "9223372036854775807")
+		if v, err := n.Int64(); err != nil || v != 9223372036854775807 {
+			t.Errorf("%s.Int64() returns %d, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "-9223372036854775808")
+		if v, err := n.Int64(); err != nil || v != -9223372036854775808 {
+			t.Errorf("%s.Int64() returns %d, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "9223372036854775808")
+		if v, err := n.Int64(); err == nil || err.(*JsonError).Code != JsonErrorNumberOutOfRange {
+			t.Errorf("%s.Int64() returns %d, %v", n.Text, v, err)
+		}
+
+		if v, err := n.Uint64(); err != nil || v != 9223372036854775808 {
+			t.Errorf("%s.Uint64() returns %d, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "18446744073709551616")
+		if v, err := n.Uint64(); err == nil || err.(*JsonError).Code != JsonErrorNumberOutOfRange {
+			t.Errorf("%s.Uint64() returns %d, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "1.8446744073709551615e19")
+		if v, err := n.Uint64(); err != nil || v != 18446744073709551615 {
+			t.Errorf("%s.Uint64() returns %d, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "-0")
+		if v, err := n.Uint64(); err != nil || v != 0 {
+			t.Errorf("%s.Uint64() returns %d, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "1.5")
+		if v, err := n.Int64(); err == nil || err.(*JsonError).Code != JsonErrorNotInteger {
+			t.Errorf("%s.Int64() returns %d, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "1e99999")
+		if v, err := n.BigInt(); err == nil || err.(*JsonError).Code != JsonErrorNumberOutOfRange {
+			t.Errorf("%s.BigInt() returns %v, %v", n.Text, v, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "123456789012345678901234567890e-1")
+		v, err := n.BigInt()
+		if err != nil || v.String() != "12345678901234567890123456789" {
+			t.Errorf("%s.BigInt() returns %v, %v", n.Text, v, err)
+		}
+	}
+}
+
+func TestNumberFloatConversion(t *testing.T) {
+	{
+		n := parseNumberForTest(t, "0.1")
+		r, err := n.BigRat()
+		if err != nil || r.String() != "1/10" {
+			t.Errorf("%s.BigRat() returns %v, %v", n.Text, r, err)
+		}
+
+		f, err := n.BigFloat()
+		if err != nil || f.Text('g', 10) != "0.1" {
+			t.Errorf("%s.BigFloat() returns %v, %v", n.Text, f, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "-12345678901234567890.125")
+		f, err := n.BigFloat()
+		if err != nil || f.Text('f', 3) != "-12345678901234567890.125" {
+			t.Errorf("%s.BigFloat() returns %v, %v", n.Text, f, err)
+		}
+	}
+
+	for _, text := range []string{"1e-100000000", "1e100000000", "1e99999999999999999999"} {
+		n := parseNumberForTest(t, text)
+		if f, err := n.BigFloat(); err == nil || err.(*JsonError).Code != JsonErrorNumberOutOfRange {
+			t.Errorf("%s.BigFloat() returns %v, %v", n.Text, f, err)
+		}
+	}
+
+	{
+		n := parseNumberForTest(t, "1e400")
+		if v, err := n.Float64(); err == nil || err.(*JsonError).Code != JsonErrorNumberOutOfRange {
+			t.Errorf("%s.Float64() returns %f, %v", n.Text, v, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package findjson
+
+// How duplicate keys in one object are handled, keys are compared after unescaping.
+type DuplicateKeyMode int
+
+const (
+	DuplicateKeyAllow  = DuplicateKeyMode(0) // duplicate keys are not checked
+	DuplicateKeyReject = DuplicateKeyMode(1) // the second occurrence is an error
+	DuplicateKeyWarn   = DuplicateKeyMode(2) // the second occurrence is reported to OnWarning
+)
+
+// Options of scanning JSON values, a nil *Options is the same as zero value.
+type Options struct {
+	// Grammar style, a preset, e.g. NormativeStyle, or any combination of features.
+	Style Style
+
+	// Report invalid UTF-8 sequences in strings, e.g. overlong encodings, surrogates encoded
+	// in UTF-8 and truncated sequences.
+	ValidateUTF8 bool
+
+	// Report escaped UTF-16 surrogates without their pairs, e.g. "\uD800".
+	RejectLoneSurrogates bool
+
+	// Provide scanners of value kinds, overriding built-in ones of Style, for root values and
+	// values nested in arrays and objects. Kinds it returns nil for use built-in scanners.
+	//
+	// Scanners from provider are also tried on chars which can not start any value in Style,
+	// e.g. backtick of template strings, a scanner returns error without consuming any char
+	// means it does not match.
+	Provider JsonScannerProvider
+
+	// Which value is returned by FindJsonWithOptions and FindJsonMatchWithOptions if several
+	// values are found, SelectFirst by default. Finder reports all values, regardless of it.
+	Selection SelectionPolicy
+
+	// Check duplicate keys in objects.
+	DuplicateKeys DuplicateKeyMode
+
+	// Called on each warning, e.g. duplicate keys in DuplicateKeyWarn mode. Warnings are reported
+	// during scanning, even if the value is rejected later. Each one is reported once for its
+	// offset by a find call, Finder or line of LineReader, though candidates may be scanned more
+	// than once, e.g. with SelectionPolicy or SearchResumeAfterStart.
+	OnWarning func(warning *JsonError)
+
+	// Limits for untrusted input, 0 or negative means no limit. Each violation is reported with
+	// its own error code.
+	MaxDepth        int // nesting depth of arrays and objects, the outermost one is 1
+	MaxValueLength  int // bytes of the whole value found
+	MaxStringLength int // bytes of a string between quotes, before unescaping
+	MaxNumberLength int // bytes of a number
+	MaxMembers      int // members of an object
+	MaxElements     int // elements of an array
+
+	// Thresholds of values reported by Finder and find functions, 0 or negative means no
+	// threshold, e.g. to suppress 1, "a" and [] in prose. Values below any threshold are skipped,
+	// arrays and objects skipped are searched inside, e.g. {"a": 1, "b": 2} in [{"a": 1, "b": 2}]
+	// is reported with MinItems 2. Searching resumes after them if they are below MinValueLength
+	// or MinDepth, which values nested in them are below too.
+	MinValueLength int // bytes of the whole value
+	MinItems       int // members of an object or elements of an array, 0 for scalars
+	MinDepth       int // nesting depth, 0 for scalars, 1 for [] and {}
+
+	// Threshold of Match.Confidence of values reported, 0 or negative means no threshold, like
+	// other thresholds above.
+	MinConfidence float64
+}
@@ -0,0 +1,79 @@
+package findjson
+
+import (
+	"math/bits"
+)
+
+// Kinds of single value, in order of index of provided scanners.
+var jsonValueKinds = []JsonValueKind{
+	JsonValueNull,
+	JsonValueBoolean,
+	JsonValueNumber,
+	JsonValueString,
+	JsonValueArray,
+	JsonValueObject,
+}
+
+// Get index of single value kind k in provided scanners.
+func kindIndex(k JsonValueKind) int {
+	return bits.TrailingZeros(uint(k))
+}
+
+// Get scanners of all kinds from provider, nil if provider is nil.
+func resolveProvider(provider JsonScannerProvider) []JsonTokenScanner {
+	if provider == nil {
+		return nil
+	}
+
+	scanners := make([]JsonTokenScanner, len(jsonValueKinds))
+	for _, k := range jsonValueKinds {
+		scanners[kindIndex(k)] = provider(k)
+	}
+
+	return scanners
+}
+
+// Scan value of kind k with scanner from provider, limits of kind are still checked.
+func (sc *jsonScanner) scanProvided(s []byte, i int, k JsonValueKind,
+	scanner JsonTokenScanner) (int, int, error) {
+
+	if k == JsonValueArray || k == JsonValueObject {
+		err := sc.enterContainer(i)
+		defer sc.leaveContainer()
+		if err != nil {
+			return i, i, err
+		}
+	}
+
+	start, end, err := scanner(s, i)
+	if k == JsonValueNumber {
+		return sc.checkNumberLength(start, end, err)
+	}
+
+	return start, end, err
+}
+
+// Try scanners from provider of each kind in mask, on a char not in FIRST SET of built-in
+// scanners, e.g. backtick of template strings. A scanner returns error without consuming any
+// char means it does not match.
+func (sc *jsonScanner) tryProvidedScanners(s []byte, i int, kind JsonValueKind) (int, int, error) {
+	for _, k := range jsonValueKinds {
+		scanner := sc.provided[kindIndex(k)]
+		if kind&k == 0 || scanner == nil {
+			continue
+		}
+
+		start, end, err := sc.scanProvided(s, i, k, scanner)
+		if end > start {
+			if sc.depth == 0 {
+				sc.kind = k
+			}
+
+			return start, end, err
+		}
+	}
+
+	v := bufferFindSample(s, i, 1)
+	err := NewJsonError(i, "unexpected first char '%s'", v)
+	return i, i, err
+}
@@ -0,0 +1,139 @@
+package findjson
+
+import (
+	"testing"
+)
+
+// Scan number with underscores as digit separators, e.g. 1_000_000.
+func scanNumberWithUnderscore(s []byte, i int) (int, int, error) {
+	l := len(s)
+	j := i
+	for j < l && (isDigit(s[j]) || (s[j] == '_' && j > i)) {
+		j++
+	}
+
+	if j <= i {
+		v := bufferFindSample(s, i, 1)
+		return i, i, NewJsonError(i, "expect digit, got '%s'", v)
+	}
+
+	return i, j, nil
+}
+
+// Scan template string quoted by backticks, without escapes.
+func scanBacktickString(s []byte, i int) (int, int, error) {
+	if s[i] != '`' {
+		return GetScannerInJNS(JsonValueString)(s, i)
+	}
+
+	l := len(s)
+	j := i + 1
+	for j < l && s[j] != '`' {
+		j++
+	}
+
+	if j >= l {
+		return i, j, NewJsonError(j, "expect backtick, got 'EOF'")
+	}
+
+	return i, j + 1, nil
+}
+
+func testProvider(kind JsonValueKind) JsonTokenScanner {
+	switch kind {
+	case JsonValueNumber:
+		return scanNumberWithUnderscore
+
+	case JsonValueString:
+		return scanBacktickString
+	}
+
+	return nil
+}
+
+func TestFindJsonWithProvider(t *testing.T) {
+	options := &Options{
+		Provider: testProvider,
+	}
+
+	{
+		//           0         1         2         3
+		//           0123456789012345678901234567890123
+		s := []byte("value = {\"a\": 1_000, \"b\": [`x`, 2]};")
+		//                   |<------------------------>|
+		start, end, err := FindJsonWithOptions(s, 0, JsonValueObject, options)
+		if err != nil || start != 8 || end != 35 {
+			t.Errorf("FindJsonWithOptions(s, 0) returns %d, %d, %v", start, end, err)
+		}
+
+		start, end, err = FindJson(s, 0, JsonValueObject)
+		if err == nil {
+			t.Errorf("FindJson(s, 0) returns %d, %d, nil", start, end)
+		}
+	}
+
+	{
+		//           0         1         2
+		//           0123456789012345678901234
+		s := []byte("say `hello` to 1_000 people")
+		got := make([]string, 0)
+		i := 0
+		for i < len(s) {
+			start, end, err := FindJsonWithOptions(s, i, JsonValueString|JsonValueNumber, options)
+			if err == nil {
+				got = append(got, string(s[start:end]))
+			}
+
+			i = end
+		}
+
+		exp := []string{"`hello`", "1_000"}
+		if len(got) != len(exp) {
+			t.Fatalf("got %v, expected %v", got, exp)
+		}
+
+		for i, v := range exp {
+			if got[i] != v {
+				t.Errorf("exp[%d](%s) != got[%d](%s)", i, v, i, got[i])
+			}
+		}
+	}
+
+	{
+		s := []byte("[1_000_000]")
+		limited := &Options{
+			Provider:        testProvider,
+			MaxNumberLength: 5,
+		}
+
+		start, end, err := FindJsonWithOptions(s, 0, JsonValueArray, limited)
+		if err == nil || err.(*JsonError).Code != JsonErrorNumberTooLong {
+			t.Errorf("FindJsonWithOptions(s, 0) returns %d, %d, %v", start, end, err)
+		}
+	}
+}
+
+func TestFindJsonWithProviderOfContainer(t *testing.T) {
+	// scanner of arrays from provider also applies limits of depth.
+	provider := func(kind JsonValueKind) JsonTokenScanner {
+		if kind == JsonValueArray {
+			return scanJsonArrayJSS
+		}
+
+		return nil
+	}
+
+	s := []byte("[[1, 2,], 3,]")
+	options := &Options{Provider: provider}
+	start, end, err := FindJsonWithOptions(s, 0, JsonValueArray, options)
+	if err != nil || start != 0 || end != len(s) {
+		t.Errorf("FindJsonWithOptions(s, 0) returns %d, %d, %v", start, end, err)
+	}
+
+	sc := newJsonScanner(&Options{Provider: provider, MaxDepth: 1})
+	sc.depth = 1 // as if nested in another array
+	start, end, err = sc.scanValue(s, 0, JsonValueArray)
+	if err == nil || err.(*JsonError).Code != JsonErrorTooDeep {
+		t.Errorf("scanValue(s, 0) returns %d, %d, %v", start, end, err)
+	}
+}
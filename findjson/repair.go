@@ -0,0 +1,509 @@
+package findjson
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// What is done to complete a truncated value.
+type RepairAction int
+
+const (
+	RepairDropComment     = RepairAction(1) // unclosed comment at the end is dropped
+	RepairDropPartial     = RepairAction(2) // incomplete escape, UTF-8 sequence or number tail is dropped
+	RepairDropValue       = RepairAction(3) // scalar which can not be completed is dropped, e.g. "-"
+	RepairDropKey         = RepairAction(4) // key without value is dropped, with its colon
+	RepairDropComma       = RepairAction(5) // trailing comma is dropped
+	RepairCloseString     = RepairAction(6) // closing quote is appended
+	RepairCompleteLiteral = RepairAction(7) // rest of literal is appended, e.g. "e" of "tru"
+	RepairCloseArray      = RepairAction(8) // "]", or ")" of tuple, is appended
+	RepairCloseObject     = RepairAction(9) // "}" is appended
+)
+
+func (a RepairAction) String() string {
+	switch a {
+	case RepairDropComment:
+		return "drop-comment"
+
+	case RepairDropPartial:
+		return "drop-partial"
+
+	case RepairDropValue:
+		return "drop-value"
+
+	case RepairDropKey:
+		return "drop-key"
+
+	case RepairDropComma:
+		return "drop-comma"
+
+	case RepairCloseString:
+		return "close-string"
+
+	case RepairCompleteLiteral:
+		return "complete-literal"
+
+	case RepairCloseArray:
+		return "close-array"
+
+	case RepairCloseObject:
+		return "close-object"
+	}
+
+	return "unknown"
+}
+
+// One step of repairing.
+type RepairStep struct {
+	Action RepairAction
+	Offset int    // offset in input, where text is dropped from, or synthesized text is inserted at
+	Text   string // text dropped or synthesized
+}
+
+// Report of repairing, tells what is synthesized.
+type RepairReport struct {
+	Start int // start of value in input
+	End   int // end of value in input, exclusive, the end of input if truncated
+
+	// Steps in order, text dropped by a step is never included in following steps. Empty if
+	// the value is complete.
+	Steps []RepairStep
+}
+
+// Check whether the value is modified.
+func (r *RepairReport) Repaired() bool {
+	return len(r.Steps) > 0
+}
+
+const (
+	repairOpen  = 0 // after '[' or '{'
+	repairValue = 1 // after a value
+	repairComma = 2 // after ','
+	repairKey   = 3 // after a key of object
+	repairColon = 4 // after ':'
+)
+
+// Open container met when walking through a truncated value.
+type repairFrame struct {
+	closer   byte
+	state    int
+	commaAt  int // offset of the last comma, -1 if none
+	keyStart int // offset of the last key
+}
+
+func (f *repairFrame) isObject() bool {
+	return f.closer == jsonRBrace
+}
+
+func (f *repairFrame) expectKey() bool {
+	return f.isObject() && (f.state == repairOpen || f.state == repairComma)
+}
+
+// State of repairing s[start:], which is a truncated value.
+type jsonRepairer struct {
+	sc     *jsonScanner
+	s      []byte
+	stack  []repairFrame
+	cut    int // s[start:cut] is kept
+	suffix []byte
+	steps  []RepairStep
+}
+
+func (r *jsonRepairer) top() *repairFrame {
+	if len(r.stack) == 0 {
+		return nil
+	}
+
+	return &r.stack[len(r.stack)-1]
+}
+
+func (r *jsonRepairer) addStep(action RepairAction, offset int, text []byte) {
+	r.steps = append(r.steps, RepairStep{Action: action, Offset: offset, Text: string(text)})
+}
+
+// Drop s[offset:cut].
+func (r *jsonRepairer) drop(action RepairAction, offset int) {
+	r.addStep(action, offset, r.s[offset:r.cut])
+	r.cut = offset
+}
+
+// Append synthesized text.
+func (r *jsonRepairer) append(action RepairAction, text []byte) {
+	r.addStep(action, r.cut, text)
+	r.suffix = append(r.suffix, text...)
+}
+
+// A key or value ends at offset, keys start at start.
+func (r *jsonRepairer) token(start int) {
+	f := r.top()
+	if f == nil {
+		return
+	}
+
+	if f.expectKey() {
+		f.state = repairKey
+		f.keyStart = start
+
+	} else {
+		f.state = repairValue
+	}
+}
+
+func isRepairDelimiter(c byte) bool {
+	switch c {
+	case jsonComma, jsonColon, jsonRBracket, jsonRBrace, jsonRParen, jsonLBracket, jsonLBrace,
+		jsonLParen, jsonQuote, jsonSingleQuote, jsonSlash:
+		return true
+	}
+
+	return isWhiteSpace(c)
+}
+
+// Get end of string at s[i], after the closing quote, -1 if it is not closed.
+func repairStringEnd(s []byte, i int) int {
+	quote := s[i]
+	l := len(s)
+	j := i + 1
+	for j < l {
+		switch s[j] {
+		case jsonBackslash:
+			j += 2
+
+		case quote:
+			return j + 1
+
+		default:
+			j++
+		}
+	}
+
+	return -1
+}
+
+// Walk through s[i:] and find the unfinished token at the end, returns its start and whether it
+// is a string, -1 if there is none. Stack of open containers is left in r. Walking stops at
+// tokens after offset stop, e.g. the offset of error before the end of s, tokens after it mean
+// the value fails in the middle.
+func (r *jsonRepairer) walk(i int, stop int) (int, bool) {
+	s := r.s
+	l := len(s)
+	j := i
+	for j < l && j <= stop {
+		c := s[j]
+		switch {
+		case isWhiteSpace(c):
+			j++
+
+		case c == jsonSlash && r.sc.allow(StyleComments) && j+1 < l &&
+			(s[j+1] == jsonSlash || s[j+1] == jsonAsterisk):
+			end, closed := commentEnd(s, j)
+			if !closed {
+				r.drop(RepairDropComment, j)
+				return -1, false
+			}
+
+			j = end
+
+		case c == jsonQuote || c == jsonSingleQuote:
+			end := repairStringEnd(s, j)
+			if end < 0 {
+				return j, true
+			}
+
+			r.token(j)
+			j = end
+
+		case c == jsonLBracket || c == jsonLBrace || c == jsonLParen:
+			closer := byte(jsonRBracket)
+			if c == jsonLBrace {
+				closer = jsonRBrace
+
+			} else if c == jsonLParen {
+				closer = jsonRParen
+			}
+
+			r.stack = append(r.stack, repairFrame{closer: closer, commaAt: -1})
+			j++
+
+		case c == jsonRBracket || c == jsonRBrace || c == jsonRParen:
+			if len(r.stack) > 0 {
+				r.stack = r.stack[:len(r.stack)-1]
+			}
+
+			r.token(j)
+			j++
+
+		case c == jsonComma:
+			if f := r.top(); f != nil {
+				f.state = repairComma
+				f.commaAt = j
+			}
+
+			j++
+
+		case c == jsonColon:
+			if f := r.top(); f != nil {
+				f.state = repairColon
+			}
+
+			j++
+
+		default:
+			k := j + 1 // at least one char, e.g. '/' when comments are not allowed
+			for k < l && !isRepairDelimiter(s[k]) {
+				k++
+			}
+
+			if k >= l {
+				return j, false
+			}
+
+			r.token(j)
+			j = k
+		}
+	}
+
+	return -1, false
+}
+
+// Get end of string content at s[i:], without incomplete escape and UTF-8 sequence at the end.
+func repairStringContentEnd(s []byte, i int) int {
+	l := len(s)
+	j := i + 1
+	for j < l {
+		if s[j] != jsonBackslash {
+			j++
+			continue
+		}
+
+		if j+1 >= l || (s[j+1] == jsonUnicode && j+6 > l) {
+			return j
+		}
+
+		j += 2
+	}
+
+	k := l
+	for k > i+1 && k > l-utf8.UTFMax && !utf8.RuneStart(s[k-1]) {
+		k--
+	}
+
+	if k > i+1 && !utf8.FullRune(s[k-1:l]) {
+		return k - 1
+	}
+
+	return l
+}
+
+// Literals of style, which truncated literals are completed to.
+func repairLiterals(style Style) [][]byte {
+	literals := [][]byte{jsonLiteralTrue, jsonLiteralFalse, jsonLiteralNull}
+	if style&StylePythonLiterals != 0 {
+		literals = append(literals, pythonLiteralTrue, pythonLiteralFalse, pythonLiteralNone)
+	}
+
+	if style&StyleSpecialNumbers != 0 {
+		literals = append(literals, []byte("NaN"), []byte("Infinity"), []byte("-Infinity"),
+			[]byte("+Infinity"))
+	}
+
+	return literals
+}
+
+// Complete truncated scalar s[i:], returns false if it can not be completed or trimmed.
+func (r *jsonRepairer) repairScalar(i int, errOffset int) bool {
+	s := r.s
+	text := s[i:]
+	var completion []byte
+	for _, literal := range repairLiterals(r.sc.style) {
+		if len(text) == 1 && (text[0] == jsonSignNegative || text[0] == jsonSignPositive) {
+			// sign is more likely a truncated number
+			break
+		}
+
+		if len(literal) > len(text) && bytes.HasPrefix(literal, text) {
+			if completion != nil {
+				// ambiguous, e.g. N of NaN and None
+				completion = nil
+				break
+			}
+
+			completion = literal[len(text):]
+		}
+	}
+
+	if completion != nil {
+		r.append(RepairCompleteLiteral, completion)
+		return true
+	}
+
+	if errOffset < len(s) {
+		// not a truncated number
+		return false
+	}
+
+	scalars := JsonValueNull | JsonValueBoolean | JsonValueNumber
+	for n := len(text); n > 0; n-- {
+		end := i + n
+		if _, e, err := r.sc.scanValue(s[:end], i, scalars); err == nil && e == end {
+			if end < len(s) {
+				r.drop(RepairDropPartial, end)
+			}
+
+			return true
+		}
+	}
+
+	r.drop(RepairDropValue, i)
+	return true
+}
+
+// Drop dangling key and trailing comma of the innermost container, and close all containers.
+func (r *jsonRepairer) closeContainers() {
+	if f := r.top(); f != nil {
+		if f.isObject() && (f.state == repairKey || f.state == repairColon) {
+			r.drop(RepairDropKey, f.keyStart)
+			f.state = repairComma
+			if f.commaAt < 0 {
+				f.state = repairOpen
+			}
+		}
+
+		if f.state == repairComma && f.commaAt >= 0 {
+			r.drop(RepairDropComma, f.commaAt)
+		}
+	}
+
+	for k := len(r.stack) - 1; k >= 0; k-- {
+		closer := r.stack[k].closer
+		if closer == jsonRBrace {
+			r.append(RepairCloseObject, []byte{closer})
+
+		} else {
+			r.append(RepairCloseArray, []byte{closer})
+		}
+	}
+}
+
+// Repair truncated value s[start:], which failed with err. Returns false if it is not truncated.
+func (r *jsonRepairer) repair(start int, err *JsonError) bool {
+	l := len(r.s)
+	tail, isString := r.walk(start, err.Offset)
+	if tail < 0 {
+		// truncated between tokens, e.g. "[1, " or "{"a": 1 /* comment"
+		return err.Offset >= l || err.Offset >= r.cut
+	}
+
+	if err.Offset < tail {
+		return false
+	}
+
+	f := r.top()
+	if f != nil && f.expectKey() {
+		// dangling key, string or identifier
+		f.state = repairKey
+		f.keyStart = tail
+
+	} else if f != nil && (f.state == repairValue || f.state == repairKey) {
+		return false
+
+	} else if isString {
+		end := repairStringContentEnd(r.s, tail)
+		if end < l {
+			r.drop(RepairDropPartial, end)
+		}
+
+		r.append(RepairCloseString, r.s[tail:tail+1])
+		r.token(tail)
+
+	} else if !r.repairScalar(tail, err.Offset) {
+		return false
+
+	} else if r.cut > tail {
+		r.token(tail)
+	}
+
+	if f == nil && r.cut <= start {
+		// nothing left
+		return false
+	}
+
+	return true
+}
+
+// Repair truncated JSON value in s, start from offset i, with options, nil for default. The
+// first candidate which is complete or failed at the end of s is repaired, e.g. LLM streaming
+// outputs and truncated log lines after a prefix.
+//
+// A candidate failed at the end of s is completed: unclosed strings, arrays and objects are
+// closed, truncated literals are completed, incomplete escapes, UTF-8 sequences and number
+// tails are dropped, dangling keys and trailing commas are dropped. Returns a new buffer of the
+// repaired value, and a report of what is synthesized. A value which is complete is returned as
+// is, with empty report.
+//
+// Candidates failed before the end of s are skipped, searching resumes where they fail, as
+// Finder does. If no candidate is repaired, the error of the first failed one is returned.
+// Violations of limits in options are never repaired, searching stops at them.
+func RepairJson(s []byte, i int, kind JsonValueKind, options *Options) ([]byte, *RepairReport, error) {
+	sc := newJsonScanner(options)
+	var first *JsonError
+	for {
+		m := sc.findMatch(s, i, kind)
+		if m.Found() {
+			report := &RepairReport{Start: m.Start, End: m.End}
+			return append([]byte(nil), m.Bytes()...), report, nil
+		}
+
+		if first == nil {
+			first = m.Err
+		}
+
+		if m.Kind == 0 || (m.Err.Code != JsonErrorSyntax && m.Err.Code != JsonErrorInvalidUTF8) {
+			return nil, nil, first
+		}
+
+		if result, report := repairCandidate(s, m, kind, sc); result != nil {
+			return result, report, nil
+		}
+
+		if m.Next() > m.Start {
+			i = m.Next()
+
+		} else {
+			i = m.Start + 1
+		}
+	}
+}
+
+// Repair candidate m failed in s, returns nil if it is not truncated at the end of s.
+func repairCandidate(s []byte, m Match, kind JsonValueKind, sc *jsonScanner) ([]byte, *RepairReport) {
+	r := &jsonRepairer{
+		sc:  sc.fork(),
+		s:   s,
+		cut: len(s),
+	}
+
+	if !r.repair(m.Start, m.Err) {
+		return nil, nil
+	}
+
+	r.closeContainers()
+	result := make([]byte, 0, r.cut-m.Start+len(r.suffix))
+	result = append(result, s[m.Start:r.cut]...)
+	result = append(result, r.suffix...)
+
+	// the repaired value MUST be accepted as a whole, warnings are reported on s already
+	check := *sc.options
+	check.OnWarning = nil
+	if _, end, err := newJsonScanner(&check).scanRootValue(result, 0, kind); err != nil || end != len(result) {
+		return nil, nil
+	}
+
+	report := &RepairReport{
+		Start: m.Start,
+		End:   len(s),
+		Steps: r.steps,
+	}
+
+	return result, report
+}
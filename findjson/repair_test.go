@@ -0,0 +1,193 @@
+package findjson
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRepairJson(t *testing.T) {
+	type repairCase struct {
+		s       string
+		style   Style
+		exp     string
+		actions []RepairAction
+	}
+
+	caseList := []repairCase{
+		{`{"a": [1, 2, {"b": "hel`, NormativeStyle, `{"a": [1, 2, {"b": "hel"}]}`,
+			[]RepairAction{RepairCloseString, RepairCloseObject, RepairCloseArray, RepairCloseObject}},
+		{`data: {"a": 1, "b`, NormativeStyle, `{"a": 1}`,
+			[]RepairAction{RepairDropKey, RepairDropComma, RepairCloseObject}},
+		{`{"a": 1, "b":  `, NormativeStyle, `{"a": 1}`,
+			[]RepairAction{RepairDropKey, RepairDropComma, RepairCloseObject}},
+		{`{"a"`, NormativeStyle, `{}`, []RepairAction{RepairDropKey, RepairCloseObject}},
+		{`[1, 2, `, NormativeStyle, `[1, 2]`, []RepairAction{RepairDropComma, RepairCloseArray}},
+		{`[1, -`, NormativeStyle, `[1]`,
+			[]RepairAction{RepairDropValue, RepairDropComma, RepairCloseArray}},
+		{`[1.5e+`, NormativeStyle, `[1.5]`, []RepairAction{RepairDropPartial, RepairCloseArray}},
+		{`[12`, NormativeStyle, `[12]`, []RepairAction{RepairCloseArray}},
+		{`[true, nu`, NormativeStyle, `[true, null]`,
+			[]RepairAction{RepairCompleteLiteral, RepairCloseArray}},
+		{`{"a": fals`, NormativeStyle, `{"a": false}`,
+			[]RepairAction{RepairCompleteLiteral, RepairCloseObject}},
+		{`["a\u00`, NormativeStyle, `["a"]`,
+			[]RepairAction{RepairDropPartial, RepairCloseString, RepairCloseArray}},
+		{`["a\`, NormativeStyle, `["a"]`,
+			[]RepairAction{RepairDropPartial, RepairCloseString, RepairCloseArray}},
+		{"[\"\xe4\xb8\xad\xe6\x96", NormativeStyle, "[\"\xe4\xb8\xad\"]",
+			[]RepairAction{RepairDropPartial, RepairCloseString, RepairCloseArray}},
+		{`"unclosed`, NormativeStyle, `"unclosed"`, []RepairAction{RepairCloseString}},
+		{`[[[`, NormativeStyle, `[[[]]]`,
+			[]RepairAction{RepairCloseArray, RepairCloseArray, RepairCloseArray}},
+		{`{a: ['b', /* c`, JavaScriptLiteralStyle, `{a: ['b']}`,
+			[]RepairAction{RepairDropComment, RepairDropComma, RepairCloseArray, RepairCloseObject}},
+		{`{a: 1, b`, JavaScriptLiteralStyle, `{a: 1}`,
+			[]RepairAction{RepairDropKey, RepairDropComma, RepairCloseObject}},
+		{`{'a': (1, Tr`, PythonStyle, `{'a': (1, True)}`,
+			[]RepairAction{RepairCompleteLiteral, RepairCloseArray, RepairCloseObject}},
+		{`[0x`, StyleSpecialNumbers, `[0]`, []RepairAction{RepairDropPartial, RepairCloseArray}},
+		{`[-Inf`, StyleSpecialNumbers, `[-Infinity]`,
+			[]RepairAction{RepairCompleteLiteral, RepairCloseArray}},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		got, report, err := RepairJson(s, 0, JsonValueObject|JsonValueArray|JsonValueString, &Options{Style: c.style})
+		if err != nil || string(got) != c.exp {
+			t.Errorf("RepairJson(%s) returns %s, %v", c.s, got, err)
+			continue
+		}
+
+		if report.End != len(s) || len(report.Steps) != len(c.actions) {
+			t.Errorf("RepairJson(%s) returns report %v", c.s, report)
+			continue
+		}
+
+		for i, step := range report.Steps {
+			if step.Action != c.actions[i] {
+				t.Errorf("RepairJson(%s) step[%d] is %s, expected %s", c.s, i, step.Action, c.actions[i])
+			}
+		}
+	}
+}
+
+func TestRepairJsonReport(t *testing.T) {
+	//           0         1         2
+	//           0123456789012345678901234
+	s := []byte(`x = {"a": [1, 2, 3], "b": `)
+	got, report, err := RepairJson(s, 0, JsonValueObject, nil)
+	if err != nil || string(got) != `{"a": [1, 2, 3]}` {
+		t.Fatalf("RepairJson() returns %s, %v", got, err)
+	}
+
+	exp := []RepairStep{
+		{RepairDropKey, 21, `"b": `},
+		{RepairDropComma, 19, `, `},
+		{RepairCloseObject, 19, `}`},
+	}
+
+	if report.Start != 4 || report.End != len(s) || !report.Repaired() {
+		t.Errorf("report is %v", report)
+	}
+
+	for i, step := range exp {
+		if report.Steps[i] != step {
+			t.Errorf("step[%d] is %v, expected %v", i, report.Steps[i], step)
+		}
+	}
+}
+
+func TestRepairJsonNotRepaired(t *testing.T) {
+	// complete values are returned as is
+	s := []byte(`x = [1, 2] y`)
+	got, report, err := RepairJson(s, 0, JsonValueArray, nil)
+	if err != nil || string(got) != `[1, 2]` || report.Repaired() || report.Start != 4 || report.End != 10 {
+		t.Errorf("RepairJson(%s) returns %s, %v, %v", s, got, report, err)
+	}
+
+	inputs := []struct {
+		s     string
+		style Style
+	}{
+		{`[1 x`, NormativeStyle},
+		{`[1x`, NormativeStyle},
+		{`{"a" 1`, NormativeStyle},
+		{`["a" "b`, NormativeStyle},
+		{`{"a": 1 tr`, NormativeStyle},
+		{`["a\x`, NormativeStyle},
+		{`[N`, PythonStyle | StyleSpecialNumbers},
+		{`-`, NormativeStyle},
+		{`no json`, NormativeStyle},
+	}
+
+	for _, c := range inputs {
+		got, report, err := RepairJson([]byte(c.s), 0, JsonValueArray|JsonValueObject, &Options{Style: c.style})
+		if err == nil {
+			t.Errorf("RepairJson(%s) returns %s, %v", c.s, got, report)
+		}
+	}
+
+	// limits are not repaired
+	s = []byte(`[[[1`)
+	if _, _, err := RepairJson(s, 0, JsonValueArray, &Options{MaxDepth: 2}); err == nil {
+		t.Errorf("RepairJson(%s) with MaxDepth returns nil error", s)
+	}
+}
+
+func TestRepairJsonAfterFailedPrefix(t *testing.T) {
+	{
+		s := []byte(`x {bad} {"a": [1, 2`)
+		got, report, err := RepairJson(s, 0, JsonValueObject, nil)
+		if err != nil || string(got) != `{"a": [1, 2]}` || report.Start != 8 || report.End != len(s) {
+			t.Errorf("RepairJson(%s) returns %s, %v, %v", s, got, report, err)
+		}
+	}
+
+	{
+		s := []byte(`[INFO] {"a": [1, 2`)
+		got, report, err := RepairJson(s, 0, JsonValueArray|JsonValueObject, nil)
+		if err != nil || string(got) != `{"a": [1, 2]}` || report.Start != 7 || report.End != len(s) {
+			t.Errorf("RepairJson(%s) returns %s, %v, %v", s, got, report, err)
+		}
+	}
+
+	{
+		// error of the first failed candidate is returned
+		s := []byte(`x {bad} [1 x`)
+		_, _, err := RepairJson(s, 0, JsonValueArray|JsonValueObject, nil)
+		if e, ok := err.(*JsonError); !ok || e.Offset != 3 {
+			t.Errorf("RepairJson(%s) returns error %v", s, err)
+		}
+	}
+}
+
+func TestRepairJsonAfterFailedPrefixLinear(t *testing.T) {
+	// O(n^2) if each failed candidate is walked to the end, never ends in time.
+	s := []byte(strings.Repeat(`[1 x {"a" 1 `, 100000) + `{"a": [1, 2`)
+	got, _, err := RepairJson(s, 0, JsonValueArray|JsonValueObject, nil)
+	if err != nil || string(got) != `{"a": [1, 2]}` {
+		t.Errorf("RepairJson() returns %s, %v", got, err)
+	}
+}
+
+func ExampleRepairJson() {
+	s := []byte(`{"id": 1, "tags": ["a", "b`)
+
+	repaired, report, err := RepairJson(s, 0, JsonValueObject, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(string(repaired))
+	for _, step := range report.Steps {
+		fmt.Printf("%s %q\n", step.Action, step.Text)
+	}
+
+	// Output:
+	// {"id": 1, "tags": ["a", "b"]}
+	// close-string "\""
+	// close-array "]"
+	// close-object "}"
+}
@@ -1,5 +1,11 @@
 package findjson
 
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
 const (
 	jsonBackslash     = '\\'
 	jsonSignPositive  = '+'
@@ -8,12 +14,18 @@ const (
 	jsonExponentLower = 'e'
 	jsonLBracket      = '['
 	jsonRBracket      = ']'
+	jsonLParen        = '('
+	jsonRParen        = ')'
 	jsonLBrace        = '{'
 	jsonRBrace        = '}'
 	jsonColon         = ':'
 	jsonComma         = ','
 	jsonPeriod        = '.'
 	jsonQuote         = '"'
+	jsonSingleQuote   = '\''
+	jsonSlash         = '/'
+	jsonAsterisk      = '*'
+	jsonNewLine       = '\n'
 	jsonDigitZero     = '0'
 	jsonUnicode       = 'u'
 )
@@ -22,6 +34,10 @@ var (
 	jsonLiteralTrue  = []byte("true")
 	jsonLiteralFalse = []byte("false")
 	jsonLiteralNull  = []byte("null")
+
+	pythonLiteralTrue  = []byte("True")
+	pythonLiteralFalse = []byte("False")
+	pythonLiteralNone  = []byte("None")
 )
 
 func bufferStartsWith(s []byte, i int, prefix []byte) (bool, int) {
@@ -175,6 +191,16 @@ func scanJsonNumber(s []byte, i int) (int, int, error) {
 		return i, j, err
 	}
 
+	_, j, err = scanFractionAndExponent(s, j)
+	return i, j, err
+}
+
+// Scan optional fraction and exponent parts of number.
+func scanFractionAndExponent(s []byte, i int) (int, int, error) {
+	var err error
+	l := len(s)
+	j := i
+
 	if j < l && s[j] == jsonPeriod {
 		// fraction
 		_, j, err = scanDigits(s, j+1)
@@ -199,27 +225,219 @@ func scanJsonNumber(s []byte, i int) (int, int, error) {
 	return i, j, err
 }
 
-func scanJsonString(s []byte, i int) (int, int, error) {
+// Scanner context, holds options and state shared by nested values.
+type jsonScanner struct {
+	options  *Options
+	style    Style              // options.Style with presets expanded to features
+	provided []JsonTokenScanner // scanners from provider, indexed by kindIndex
+	depth    int
+	maxDepth int           // max depth reached, reset for each root value
+	kind     JsonValueKind // kind of the last root value
+	items    int           // items of the last array or object scanned, the root one at last
+	memo     *scanMemo     // results of arrays and objects, nil if not memoized
+	warned   map[int]bool  // offsets of warnings reported, shared by scanners of the same input
+}
+
+func newJsonScanner(options *Options) *jsonScanner {
+	if options == nil {
+		options = &Options{}
+	}
+
+	sc := &jsonScanner{
+		options:  options,
+		style:    options.Style.features(),
+		provided: resolveProvider(options.Provider),
+	}
+
+	if options.OnWarning != nil {
+		sc.warned = make(map[int]bool)
+	}
+
+	return sc
+}
+
+// Create scanner with the same options, sharing warnings reported by sc, so they are not
+// reported again.
+func (sc *jsonScanner) fork() *jsonScanner {
+	f := newJsonScanner(sc.options)
+	f.warned = sc.warned
+	return f
+}
+
+func newJsonScannerWithStyle(style Style) *jsonScanner {
+	return newJsonScanner(&Options{Style: style})
+}
+
+// Report warning to OnWarning, once for each offset, though the same input may be scanned more
+// than once, e.g. candidates failed and searched again.
+func (sc *jsonScanner) warn(e *JsonError) {
+	if sc.options.OnWarning == nil || sc.warned[e.Offset] {
+		return
+	}
+
+	sc.warned[e.Offset] = true
+	sc.options.OnWarning(e)
+}
+
+// Check whether grammar feature is enabled.
+func (sc *jsonScanner) allow(feature Style) bool {
+	return sc.style&feature != 0
+}
+
+// Get scanner of kind bound to this scanner context.
+func (sc *jsonScanner) getScanner(kind JsonValueKind) JsonTokenScanner {
+	switch kind {
+	case JsonValueNull, JsonValueBoolean:
+		return sc.scanLiteral
+
+	case JsonValueNumber:
+		return sc.scanNumber
+
+	case JsonValueString:
+		return sc.scanString
+
+	case JsonValueArray:
+		return sc.scanArray
+
+	case JsonValueObject:
+		return sc.scanObject
+	}
+
+	return nil
+}
+
+var (
+	jsonBlockCommentEnd = []byte("*/")
+)
+
+// Jump over white spaces, and comments if allowed.
+func (sc *jsonScanner) skipSpace(s []byte, i int) int {
+	j := jumpNextNonWhiteSpace(s, i)
+	if j < len(s) && s[j] == jsonSlash && sc.allow(StyleComments) {
+		return sc.skipComments(s, j)
+	}
+
+	return j
+}
+
+// Jump over comments and white spaces between them, unclosed comment goes to EOF.
+func (sc *jsonScanner) skipComments(s []byte, i int) int {
+	l := len(s)
+	j := i
+	for j+1 < l && s[j] == jsonSlash && (s[j+1] == jsonSlash || s[j+1] == jsonAsterisk) {
+		if sc.memo != nil {
+			j = sc.memo.commentEnd(s, j)
+
+		} else {
+			j, _ = commentEnd(s, j)
+		}
+
+		j = jumpNextNonWhiteSpace(s, j)
+	}
+
+	return j
+}
+
+// Get end of comment at s[i], after the new line or "*/", EOF if not closed.
+func commentEnd(s []byte, i int) (int, bool) {
+	if s[i+1] == jsonSlash {
+		if k := bytes.IndexByte(s[i+2:], jsonNewLine); k >= 0 {
+			return i + 2 + k + 1, true
+		}
+
+	} else if k := bytes.Index(s[i+2:], jsonBlockCommentEnd); k >= 0 {
+		return i + 2 + k + len(jsonBlockCommentEnd), true
+	}
+
+	return len(s), false
+}
+
+// Enter an array or object at position i, MUST be paired with leaveContainer, even on error.
+func (sc *jsonScanner) enterContainer(i int) error {
+	sc.depth++
+	if sc.depth > sc.maxDepth {
+		sc.maxDepth = sc.depth
+	}
+
+	if max := sc.options.MaxDepth; max > 0 && sc.depth > max {
+		return NewJsonErrorWithCode(JsonErrorTooDeep, i, "nesting depth exceeds %d", max)
+	}
+
+	return nil
+}
+
+func (sc *jsonScanner) leaveContainer() {
+	sc.depth--
+}
+
+// Check \uXXXX escape at s[i-2:i+4] is not a lone surrogate, i is the position of the first
+// hex digit. Returns position just after the escape, or the pair of escapes.
+func scanEscapedSurrogate(s []byte, i int) (int, error) {
+	r, err := decodeHex4(s, i)
+	if err != nil {
+		return i, err
+	}
+
+	j := i + 4
+	if !utf16.IsSurrogate(r) {
+		return j, nil
+	}
+
+	if r < 0xdc00 && j+1 < len(s) && s[j] == jsonBackslash && s[j+1] == jsonUnicode {
+		// high surrogate, MUST be followed by a low surrogate
+		r2, err := decodeHex4(s, j+2)
+		if err == nil && utf16.DecodeRune(r, r2) != utf8.RuneError {
+			return j + 6, nil
+		}
+	}
+
+	err = NewJsonErrorWithCode(JsonErrorLoneSurrogate, i-2,
+		"lone surrogate '\\u%s' in string", string(s[i:j]))
+	return i - 2, err
+}
+
+func (sc *jsonScanner) scanString(s []byte, i int) (int, int, error) {
 	var err error
 	l := len(s)
 	j := i
 	quoteClosed := false
 
-	if s[j] != jsonQuote {
+	quote := s[j]
+	if quote != jsonQuote && (quote != jsonSingleQuote || !sc.allow(StyleSingleQuotes)) {
 		v := bufferFindSample(s, j, 1)
 		err = NewJsonError(j, "expect quote '\"', got '%s'", v)
 		return i, j, err
 	}
 
+	if sc.memo != nil {
+		if last, found := sc.memo.findFailedString(s, i); found {
+			return i, last.end, last.err
+		}
+	}
+
 	j++ // skip quote
+	maxLength := sc.options.MaxStringLength
 	for j < l {
 		c1 := s[j]
+		if maxLength > 0 && j-i-1 >= maxLength && (c1 != quote || j-i-1 > maxLength) {
+			// at the first byte out of limit, escapes may end beyond it
+			j = i + 1 + maxLength
+			err = NewJsonErrorWithCode(JsonErrorStringTooLong, j,
+				"string is longer than %d bytes", maxLength)
+			break
+		}
+
 		j++
 
 		if c1 == jsonBackslash {
+			if j >= l {
+				err = NewJsonError(j, "expect escape char, got 'EOF'")
+				break
+			}
+
 			c2 := s[j]
 
-			if isEscapeChar(c2) {
+			if isEscapeChar(c2) || (c2 == jsonSingleQuote && sc.allow(StyleSingleQuotes)) {
 				j++
 				// escape char
 
@@ -231,13 +449,19 @@ func scanJsonString(s []byte, i int) (int, int, error) {
 					break
 				}
 
-				if nj-j >= 4 {
-					j += 4
-
-				} else {
+				if nj-j < 4 {
 					v := bufferFindSample(s, j, 4)
 					err = NewJsonError(j, "expect 4 hex digits, got '%s'", v)
 					break
+
+				} else if sc.options.RejectLoneSurrogates {
+					j, err = scanEscapedSurrogate(s, j)
+					if err != nil {
+						break
+					}
+
+				} else {
+					j += 4
 				}
 
 			} else {
@@ -246,89 +470,168 @@ func scanJsonString(s []byte, i int) (int, int, error) {
 				break
 			}
 
-		} else if c1 == jsonQuote {
+		} else if c1 == quote {
 			quoteClosed = true
 			break
+
+		} else if isControlChar(c1) && !sc.allow(StyleControlChars) {
+			j--
+			err = NewJsonErrorWithCode(JsonErrorControlChar, j,
+				"unexpected control char 0x%02x in string", c1)
+			break
+
+		} else if c1 >= utf8.RuneSelf && sc.options.ValidateUTF8 {
+			r, size := utf8.DecodeRune(s[j-1:])
+			if r == utf8.RuneError && size <= 1 {
+				j--
+				err = NewJsonErrorWithCode(JsonErrorInvalidUTF8, j,
+					"invalid UTF-8 byte 0x%02x in string", c1)
+				break
+			}
+
+			j += size - 1
 		}
 	}
 
 	if err == nil && !quoteClosed {
 		v := bufferFindSample(s, j, 1)
-		err = NewJsonError(j, "expect quote '\"', got '%s'", v)
+		err = NewJsonError(j, "expect quote '%c', got '%s'", quote, v)
+	}
+
+	if err != nil && sc.memo != nil {
+		sc.memo.rememberString(s, i, j, err)
 	}
 
 	return i, j, err
 }
 
-func scanJsonArray(s []byte, i int, style int) (int, int, error) {
+func scanJsonStringWithStyle(s []byte, i int, style Style) (int, int, error) {
+	return newJsonScannerWithStyle(style).scanString(s, i)
+}
+
+// Scan JSON string in JSON style, raw control chars are NOT ALLOWED.
+func scanJsonString(s []byte, i int) (int, int, error) {
+	return scanJsonStringWithStyle(s, i, NormativeStyle)
+}
+
+// Scan JSON string in JavaScript style, raw control chars are ALLOWED.
+func scanJsonStringJSS(s []byte, i int) (int, int, error) {
+	return scanJsonStringWithStyle(s, i, JavaScriptStyle)
+}
+
+// Create error at i with 1 char sample, message has a '%s' for the sample.
+//
+// Array and object scanners use it instead of NewJsonError, to keep their stack frames small
+// for deeply nested values.
+func newSampleError(s []byte, i int, message string) *JsonError {
+	v := bufferFindSample(s, i, 1)
+	return NewJsonError(i, message, v)
+}
+
+// Error messages of array scanner, for arrays and tuples.
+type arrayMessages struct {
+	expectOpen              string
+	expectValueOrClose      string
+	expectCommaOrClose      string
+	expectCommaOrCloseAtEOF string
+	notClosed               string
+}
+
+var jsonArrayMessages = arrayMessages{
+	expectOpen:              "expect bracket '[', got '%s'",
+	expectValueOrClose:      "expect value or bracket ']', got '%s'",
+	expectCommaOrClose:      "expect comma ',' or bracket ']', got '%s'",
+	expectCommaOrCloseAtEOF: "expect comma',' or bracket ']', got '%s'",
+	notClosed:               "array is not close, got '%s'",
+}
+
+var pythonTupleMessages = arrayMessages{
+	expectOpen:              "expect parenthesis '(', got '%s'",
+	expectValueOrClose:      "expect value or parenthesis ')', got '%s'",
+	expectCommaOrClose:      "expect comma ',' or parenthesis ')', got '%s'",
+	expectCommaOrCloseAtEOF: "expect comma ',' or parenthesis ')', got '%s'",
+	notClosed:               "tuple is not close, got '%s'",
+}
+
+func (sc *jsonScanner) scanArray(s []byte, i int) (int, int, error) {
 	var err error
 	l := len(s)
 	j := i
 	bracketClosed := false
+	closer, messages := byte(jsonRBracket), &jsonArrayMessages
 
-	if s[j] != jsonLBracket {
-		v := bufferFindSample(s, j, 1)
-		err = NewJsonError(j, "expect bracket '[', got '%s'", v)
+	if s[j] == jsonLParen && sc.allow(StyleTuples) {
+		closer, messages = jsonRParen, &pythonTupleMessages
+
+	} else if s[j] != jsonLBracket {
+		err = newSampleError(s, j, messages.expectOpen)
 		return i, j, err
 	}
 
-	j = jumpNextNonWhiteSpace(s, j+1)
+	j = sc.skipSpace(s, j+1)
 	if j >= l {
-		v := bufferFindSample(s, j, 1)
-		err = NewJsonError(j, "expect value or bracket ']', got '%s'", v)
+		err = newSampleError(s, j, messages.expectValueOrClose)
 		return i, j, err
 
-	} else if s[j] == jsonRBracket {
+	} else if s[j] == closer {
+		sc.items = 0
 		return i, j + 1, nil
 	}
 
+	count := 0
 	for j < l {
-		j = jumpNextNonWhiteSpace(s, j)
+		j = sc.skipSpace(s, j)
 		if j >= l {
-			v := bufferFindSample(s, j, 1)
-			err := NewJsonError(j, "expect value or bracket ']', got '%s'", v)
+			err := newSampleError(s, j, messages.expectValueOrClose)
 			return i, j, err
 
-		} else if s[j] == jsonRBracket && style == JavaScriptStyle {
+		} else if s[j] == closer && sc.allow(StyleTrailingComma) {
 			j += 1
 			bracketClosed = true
 			break
+
+		} else if err = sc.checkCount(j, count, JsonValueArray); err != nil {
+			break
 		}
 
-		_, j, err = scanJsonValueByFirstSet(s, j, JsonValueAll, style)
+		count++
+
+		_, j, err = sc.scanValue(s, j, JsonValueAll)
 		if err != nil {
 			break
 		}
 
-		j = jumpNextNonWhiteSpace(s, j)
+		j = sc.skipSpace(s, j)
 		if j >= l {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect comma',' or bracket ']', got '%s'", v)
+			err = newSampleError(s, j, messages.expectCommaOrCloseAtEOF)
 
 		} else if s[j] == jsonComma {
 			j += 1
 			continue
 
-		} else if s[j] == jsonRBracket {
+		} else if s[j] == closer {
 			j += 1
 			bracketClosed = true
 
 		} else {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect comma ',' or bracket ']', got '%s'", v)
+			err = newSampleError(s, j, messages.expectCommaOrClose)
 		}
 
 		break
 	}
 
 	if err == nil && !bracketClosed {
-		v := bufferFindSample(s, j, 1)
-		err = NewJsonError(j, "array is not close, got '%s'", v)
+		err = newSampleError(s, j, messages.notClosed)
 	}
 
+	sc.items = count
 	return i, j, err
 }
 
+func scanJsonArray(s []byte, i int, style Style) (int, int, error) {
+	return newJsonScannerWithStyle(style).scanArray(s, i)
+}
+
 // Scan JSON array in JSON style, the trailing comma is NOT ALLOWED.
 func scanJsonArrayJNS(s []byte, i int) (int, int, error) {
 	return scanJsonArray(s, i, NormativeStyle)
@@ -339,74 +642,79 @@ func scanJsonArrayJSS(s []byte, i int) (int, int, error) {
 	return scanJsonArray(s, i, JavaScriptStyle)
 }
 
-func scanJsonObject(s []byte, i int, style int) (int, int, error) {
+func (sc *jsonScanner) scanObject(s []byte, i int) (int, int, error) {
 	var err error
 	l := len(s)
 	j := i
 	braceClosed := false
 
 	if s[j] != jsonLBrace {
-		v := bufferFindSample(s, j, 1)
-		err = NewJsonError(j, "expect brace '{', got '%s'", v)
+		err = newSampleError(s, j, "expect brace '{', got '%s'")
 		return i, j, err
 	}
 
-	j = jumpNextNonWhiteSpace(s, j+1)
+	j = sc.skipSpace(s, j+1)
 	if j >= l {
-		v := bufferFindSample(s, j, 1)
-		err = NewJsonError(j, "expect key string or brace '}', got '%s'", v)
+		err = newSampleError(s, j, "expect key string or brace '}', got '%s'")
 		return i, j, err
 
 	} else if s[j] == jsonRBrace {
+		sc.items = 0
 		return i, j + 1, nil
 	}
 
+	var keys map[string]bool
+	if sc.options.DuplicateKeys != DuplicateKeyAllow {
+		keys = make(map[string]bool)
+	}
+
+	count := 0
+
 	for j < l {
-		j = jumpNextNonWhiteSpace(s, j)
+		j = sc.skipSpace(s, j)
 		if j >= l {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect key string, got '%s'", v)
+			err = newSampleError(s, j, "expect key string, got '%s'")
 			return i, j, err
 
-		} else if s[j] == jsonRBrace && style == JavaScriptStyle {
+		} else if s[j] == jsonRBrace && sc.allow(StyleTrailingComma) {
 			j += 1
 			braceClosed = true
 			break
+
+		} else if err = sc.checkCount(j, count, JsonValueObject); err != nil {
+			break
 		}
 
-		_, j, err = scanJsonString(s, j)
+		count++
+		j, err = sc.scanKey(s, j, keys)
 		if err != nil {
 			return i, j, err
 		}
 
-		j = jumpNextNonWhiteSpace(s, j)
+		j = sc.skipSpace(s, j)
 		if j >= l {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect colon ':', got '%s'", v)
+			err = newSampleError(s, j, "expect colon ':', got '%s'")
 			break
 
 		} else if s[j] != jsonColon {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect colon ':', got '%s'", v)
+			err = newSampleError(s, j, "expect colon ':', got '%s'")
 			break
 		}
 
-		j = jumpNextNonWhiteSpace(s, j+1)
+		j = sc.skipSpace(s, j+1)
 		if j >= l {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect value, got '%s'", v)
+			err = newSampleError(s, j, "expect value, got '%s'")
 			break
 		}
 
-		_, j, err = scanJsonValueByFirstSet(s, j, JsonValueAll, style)
+		_, j, err = sc.scanValue(s, j, JsonValueAll)
 		if err != nil {
 			break
 		}
 
-		j = jumpNextNonWhiteSpace(s, j)
+		j = sc.skipSpace(s, j)
 		if j >= l {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect comma ',' or brace '}', got '%s'", v)
+			err = newSampleError(s, j, "expect comma ',' or brace '}', got '%s'")
 
 		} else if s[j] == jsonComma {
 			j += 1
@@ -417,21 +725,87 @@ func scanJsonObject(s []byte, i int, style int) (int, int, error) {
 			braceClosed = true
 
 		} else {
-			v := bufferFindSample(s, j, 1)
-			err = NewJsonError(j, "expect comma ',' or brace '}', got '%s'", v)
+			err = newSampleError(s, j, "expect comma ',' or brace '}', got '%s'")
 		}
 
 		break
 	}
 
 	if err == nil && !braceClosed {
-		v := bufferFindSample(s, j, 1)
-		err = NewJsonError(j, "object is not close, got '%s'", v)
+		err = newSampleError(s, j, "object is not close, got '%s'")
 	}
 
+	sc.items = count
 	return i, j, err
 }
 
+// Check count of members in object, or elements in array, before scanning the next one at i.
+func (sc *jsonScanner) checkCount(i int, count int, kind JsonValueKind) error {
+	if kind == JsonValueObject {
+		if max := sc.options.MaxMembers; max > 0 && count >= max {
+			return NewJsonErrorWithCode(JsonErrorTooManyMembers, i, "object has more than %d members", max)
+		}
+
+	} else if max := sc.options.MaxElements; max > 0 && count >= max {
+		return NewJsonErrorWithCode(JsonErrorTooManyElements, i, "array has more than %d elements", max)
+	}
+
+	return nil
+}
+
+// Scan object key at i, and check duplication if keys is not nil.
+// Returns position just after the key, or the start of a duplicate key.
+func (sc *jsonScanner) scanKey(s []byte, i int, keys map[string]bool) (int, error) {
+	var j int
+	var err error
+
+	if sc.allow(StyleUnquotedKeys) && isIdentifierStart(s[i]) {
+		j = scanIdentifier(s, i)
+	} else {
+		_, j, err = sc.scanString(s, i)
+	}
+
+	if err != nil || keys == nil {
+		return j, err
+	}
+
+	if err = sc.checkDuplicateKey(keys, s, i, j); err != nil {
+		return i, err
+	}
+
+	return j, nil
+}
+
+// Check whether key s[start:end] is already in keys, and record it.
+func (sc *jsonScanner) checkDuplicateKey(keys map[string]bool, s []byte, start int, end int) error {
+	key := s[start:end]
+	if c := s[start]; c == jsonQuote || c == jsonSingleQuote {
+		var err error
+		key, err = UnquoteString(s, start, end)
+		if err != nil {
+			return err
+		}
+	}
+
+	k := string(key)
+	if !keys[k] {
+		keys[k] = true
+		return nil
+	}
+
+	e := NewJsonErrorWithCode(JsonErrorDuplicateKey, start, "duplicate key '%s' in object", k)
+	if sc.options.DuplicateKeys == DuplicateKeyWarn {
+		sc.warn(e)
+		return nil
+	}
+
+	return e
+}
+
+func scanJsonObject(s []byte, i int, style Style) (int, int, error) {
+	return newJsonScannerWithStyle(style).scanObject(s, i)
+}
+
 // Scan JSON Object in JSON style, the trailing comma is NOT ALLOWED.
 func scanJsonObjectJNS(s []byte, i int) (int, int, error) {
 	return scanJsonObject(s, i, NormativeStyle)
@@ -442,14 +816,153 @@ func scanJsonObjectJSS(s []byte, i int) (int, int, error) {
 	return scanJsonObject(s, i, JavaScriptStyle)
 }
 
-func scanJsonValueByFirstSet(s []byte, i int, kind JsonValueKind, style int) (int, int, error) {
-	c := s[i]
-	scanner := kind.GetScanner(c, style)
-	if scanner == nil {
-		v := bufferFindSample(s, i, 1)
-		err := NewJsonError(i, "unexpected first char '%s'", v)
-		return i, i, err
+// Scan identifier, in ASCII, as unquoted key, s[i] MUST be an identifier start char.
+func scanIdentifier(s []byte, i int) int {
+	l := len(s)
+	j := i + 1
+	for j < l && isIdentifierPart(s[j]) {
+		j++
+	}
+
+	return j
+}
+
+// Scan literal, Python literals True, False and None are also accepted if allowed.
+func (sc *jsonScanner) scanLiteral(s []byte, i int) (int, int, error) {
+	var literal []byte
+	if sc.allow(StylePythonLiterals) {
+		switch s[i] {
+		case 'N':
+			literal = pythonLiteralNone
+
+		case 'T':
+			literal = pythonLiteralTrue
+
+		case 'F':
+			literal = pythonLiteralFalse
+		}
+	}
+
+	if literal == nil {
+		return scanJsonLiteral(s, i)
+	}
+
+	m, l := bufferStartsWith(s, i, literal)
+	if !m {
+		v := bufferFindSample(s, i, 5)
+		err := NewJsonError(i, "expect None, True or False, got '%s'", v)
+		return i, i + l, err
+	}
+
+	return i, i + l, nil
+}
+
+func (sc *jsonScanner) scanNumber(s []byte, i int) (int, int, error) {
+	if sc.memo != nil {
+		if last, found := sc.memo.findFailedNumber(s, i); found {
+			return i, last.end, last.err
+		}
+	}
+
+	scanner := scanJsonNumber
+	if sc.allow(StyleSpecialNumbers) {
+		scanner = scanSpecialNumber
+	}
+
+	start, end, err := scanner(s, i)
+	if err != nil && sc.memo != nil {
+		sc.memo.rememberNumber(s, i, end, err)
+	}
+
+	return sc.checkNumberLength(start, end, err)
+}
+
+// Check MaxNumberLength on result of number scanner.
+func (sc *jsonScanner) checkNumberLength(start int, end int, err error) (int, int, error) {
+	if max := sc.options.MaxNumberLength; err == nil && max > 0 && end-start > max {
+		end = start + max
+		err = NewJsonErrorWithCode(JsonErrorNumberTooLong, end, "number is longer than %d bytes", max)
+	}
+
+	return start, end, err
+}
+
+func (sc *jsonScanner) scanValue(s []byte, i int, kind JsonValueKind) (int, int, error) {
+	k := kind & firstSetKind(s[i], sc.style)
+	if k == JsonValueNull|JsonValueNumber {
+		k = kindOfCapitalN(s, i)
+	}
+
+	if sc.memo != nil && k&(JsonValueArray|JsonValueObject) != 0 {
+		return sc.scanMemoized(s, i, k)
+	}
+
+	if sc.provided != nil {
+		if k == 0 {
+			return sc.tryProvidedScanners(s, i, kind)
+
+		} else if scanner := sc.provided[kindIndex(k)]; scanner != nil {
+			return sc.scanProvided(s, i, k, scanner)
+		}
+	}
+
+	switch k {
+	case JsonValueNull, JsonValueBoolean:
+		return sc.scanLiteral(s, i)
+
+	case JsonValueNumber:
+		return sc.scanNumber(s, i)
+
+	case JsonValueString:
+		return sc.scanString(s, i)
+
+	case JsonValueArray:
+		if err := sc.enterContainer(i); err != nil {
+			sc.leaveContainer()
+			return i, i, err
+		}
+
+		start, end, err := sc.scanArray(s, i)
+		sc.leaveContainer()
+		return start, end, err
+
+	case JsonValueObject:
+		if err := sc.enterContainer(i); err != nil {
+			sc.leaveContainer()
+			return i, i, err
+		}
+
+		start, end, err := sc.scanObject(s, i)
+		sc.leaveContainer()
+		return start, end, err
+	}
+
+	v := bufferFindSample(s, i, 1)
+	err := NewJsonError(i, "unexpected first char '%s'", v)
+	return i, i, err
+}
+
+// Scan a root value, which is not nested in any array or object, with MaxValueLength checked.
+func (sc *jsonScanner) scanRootValue(s []byte, i int, kind JsonValueKind) (int, int, error) {
+	sc.maxDepth = 0
+	sc.items = 0
+	sc.kind = kind & firstSetKind(s[i], sc.style)
+	if sc.kind == JsonValueNull|JsonValueNumber {
+		sc.kind = kindOfCapitalN(s, i)
+	}
+
+	max := sc.options.MaxValueLength
+	if max <= 0 || len(s)-i <= max {
+		return sc.scanValue(s, i, kind)
+	}
+
+	// Scan on a buffer 1 byte longer than limit, any value, or error, ends after the limit means
+	// the value is too long, and those end within the limit are not affected by truncating.
+	start, end, err := sc.scanValue(s[:i+max+1], i, kind)
+	if end > i+max {
+		end = i + max
+		err = NewJsonErrorWithCode(JsonErrorValueTooLong, end, "value is longer than %d bytes", max)
 	}
 
-	return scanner(s, i)
+	return start, end, err
 }
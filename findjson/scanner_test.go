@@ -1,6 +1,7 @@
 package findjson
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 	"testing"
@@ -516,6 +517,25 @@ func TestScanJsonStringFailure(t *testing.T) {
 
 	}
 
+	{
+		//           0         1
+		//           0123456789012345
+		s := []byte(`"the quick \`)
+		//           |           ^
+		start, end, err := scanJsonString(s, 0)
+		if err == nil {
+			t.Fatalf("scanJsonString(s, 0) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 12: expect escape char, got 'EOF'" {
+			t.Errorf("scanJsonString(s, 0) returns %d, %d, %s", start, end, err)
+		}
+
+		if start != 0 || end != 12 {
+			t.Errorf("scanJsonString(s, 0) returns %d, %d, %s", start, end, err)
+		}
+	}
+
 	{
 		//           0         1         2
 		//           012345678901234567890
@@ -537,6 +557,164 @@ func TestScanJsonStringFailure(t *testing.T) {
 	}
 }
 
+func TestScanJsonStringControlChar(t *testing.T) {
+	{
+		//           0         1
+		//           0123456789012345
+		s := []byte("\"the quick\nbrown fox\"")
+		//           |         ^
+		start, end, err := scanJsonString(s, 0)
+		if err == nil {
+			t.Fatalf("scanJsonString(s, 0) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 10: unexpected control char 0x0a in string" {
+			t.Errorf("scanJsonString(s, 0) returns %d, %d, %s", start, end, err)
+		}
+
+		if code := err.(*JsonError).Code; code != JsonErrorControlChar {
+			t.Errorf("scanJsonString(s, 0) returns error code %d", code)
+		}
+
+		if start != 0 || end != 10 {
+			t.Errorf("scanJsonString(s, 0) returns %d, %d, %s", start, end, err)
+		}
+	}
+
+	{
+		s := []byte("\"tab\tand\x00nul\"")
+		start, end, err := scanJsonString(s, 0)
+		if err == nil {
+			t.Fatalf("scanJsonString(s, 0) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 4: unexpected control char 0x09 in string" {
+			t.Errorf("scanJsonString(s, 0) returns %d, %d, %s", start, end, err)
+		}
+	}
+
+	caseList := scannerCorrectCases{
+		"\"the quick\nbrown fox\"",
+		"\"tab\tand\x00nul\"",
+		"\"\x7f is not a control char\"",
+	}
+
+	caseList.On(t, scanJsonStringJSS)
+}
+
+func TestScanJsonObjectControlCharInKey(t *testing.T) {
+	s := []byte("{\"multi\nline\": 1}")
+	start, end, err := scanJsonObjectJNS(s, 0)
+	if err == nil {
+		t.Fatalf("scanJsonObjectJNS(s, 0) returns %d, %d, nil", start, end)
+	}
+
+	if code := err.(*JsonError).Code; code != JsonErrorControlChar {
+		t.Errorf("scanJsonObjectJNS(s, 0) returns %d, %d, %s", start, end, err)
+	}
+
+	start, end, err = scanJsonObjectJSS(s, 0)
+	if err != nil || start != 0 || end != len(s) {
+		t.Errorf("scanJsonObjectJSS(s, 0) returns %d, %d, %v", start, end, err)
+	}
+}
+
+func TestScanJsonStringValidateUTF8(t *testing.T) {
+	sc := newJsonScanner(&Options{ValidateUTF8: true})
+
+	caseList := scannerCorrectCases{
+		`"abc"`,
+		`"été 直接 😀"`,
+		"\"\xef\xbf\xbd replacement char\"",
+		"\"invalid escape \\ud800 is not checked\"",
+	}
+
+	caseList.On(t, sc.scanString)
+
+	type failureCase struct {
+		s      string
+		offset int
+		msg    string
+	}
+
+	failureList := []failureCase{
+		{"\"abc\xffdef\"", 4, "invalid UTF-8 byte 0xff in string"},
+		{"\"overlong \xc0\x80\"", 10, "invalid UTF-8 byte 0xc0 in string"},
+		{"\"surrogate \xed\xa0\x80\"", 11, "invalid UTF-8 byte 0xed in string"},
+		{"\"truncated \xe4\xb8\"", 11, "invalid UTF-8 byte 0xe4 in string"},
+		{"\"truncated at EOF \xe4\xb8", 18, "invalid UTF-8 byte 0xe4 in string"},
+		{"\"continuation \x80\"", 14, "invalid UTF-8 byte 0x80 in string"},
+	}
+
+	for _, c := range failureList {
+		s := []byte(c.s)
+		start, end, err := sc.scanString(s, 0)
+		if err == nil {
+			t.Errorf("scanString(%q) returns %d, %d, nil", c.s, start, end)
+			continue
+		}
+
+		e := err.(*JsonError)
+		if e.Offset != c.offset || e.Message != c.msg || e.Code != JsonErrorInvalidUTF8 {
+			t.Errorf("scanString(%q) returns %d, %d, %s", c.s, start, end, err)
+		}
+
+		if start != 0 || end != c.offset {
+			t.Errorf("scanString(%q) returns %d, %d, %s", c.s, start, end, err)
+		}
+	}
+
+	{
+		s := []byte("\"abc\xffdef\"")
+		start, end, err := scanJsonString(s, 0)
+		if err != nil || start != 0 || end != len(s) {
+			t.Errorf("scanJsonString(%q) returns %d, %d, %v", s, start, end, err)
+		}
+	}
+}
+
+func TestScanJsonStringRejectLoneSurrogates(t *testing.T) {
+	sc := newJsonScanner(&Options{RejectLoneSurrogates: true})
+
+	caseList := scannerCorrectCases{
+		`"abc\u4dae"`,
+		`"\ud83d\ude00"`,
+		`"\uD83D\uDE00 smile"`,
+		`"\uffff"`,
+	}
+
+	caseList.On(t, sc.scanString)
+
+	type failureCase struct {
+		s      string
+		offset int
+		msg    string
+	}
+
+	failureList := []failureCase{
+		{`"\ud800"`, 1, "lone surrogate '\\ud800' in string"},
+		{`"abc\uDC00def"`, 4, "lone surrogate '\\uDC00' in string"},
+		{`"\ud83d\u0041"`, 1, "lone surrogate '\\ud83d' in string"},
+		{`"\ud83d\ud83d"`, 1, "lone surrogate '\\ud83d' in string"},
+		{`"\ude00\ud83d"`, 1, "lone surrogate '\\ude00' in string"},
+		{`"\ud83d\n"`, 1, "lone surrogate '\\ud83d' in string"},
+	}
+
+	for _, c := range failureList {
+		s := []byte(c.s)
+		start, end, err := sc.scanString(s, 0)
+		if err == nil {
+			t.Errorf("scanString(%s) returns %d, %d, nil", c.s, start, end)
+			continue
+		}
+
+		e := err.(*JsonError)
+		if e.Offset != c.offset || e.Message != c.msg || e.Code != JsonErrorLoneSurrogate {
+			t.Errorf("scanString(%s) returns %d, %d, %s", c.s, start, end, err)
+		}
+	}
+}
+
 func TestScanJsonArraySuccessJNS(t *testing.T) {
 	caseList := scannerCorrectCases{
 		"[]",
@@ -1027,6 +1205,259 @@ func TestScanJsonObjectFailureJNS(t *testing.T) {
 	}
 }
 
+func TestScanJsonObjectDuplicateKeys(t *testing.T) {
+	//           0         1         2         3
+	//           0123456789012345678901234567890123
+	s := []byte(`{"a": 1, "b": {"a": 2}, "\u0061": 3}`)
+	//                                   ^
+
+	{
+		start, end, err := scanJsonObjectJNS(s, 0)
+		if err != nil || start != 0 || end != len(s) {
+			t.Errorf("scanJsonObjectJNS(s, 0) returns %d, %d, %v", start, end, err)
+		}
+	}
+
+	{
+		sc := newJsonScanner(&Options{DuplicateKeys: DuplicateKeyReject})
+		start, end, err := sc.scanObject(s, 0)
+		if err == nil {
+			t.Fatalf("scanObject(s, 0) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 24: duplicate key 'a' in object" {
+			t.Errorf("scanObject(s, 0) returns %d, %d, %s", start, end, err)
+		}
+
+		if code := err.(*JsonError).Code; code != JsonErrorDuplicateKey {
+			t.Errorf("scanObject(s, 0) returns error code %d", code)
+		}
+
+		if start != 0 || end != 24 {
+			t.Errorf("scanObject(s, 0) returns %d, %d, %s", start, end, err)
+		}
+	}
+
+	{
+		warnings := make([]*JsonError, 0)
+		sc := newJsonScanner(&Options{
+			DuplicateKeys: DuplicateKeyWarn,
+			OnWarning: func(w *JsonError) {
+				warnings = append(warnings, w)
+			},
+		})
+
+		start, end, err := sc.scanObject(s, 0)
+		if err != nil || start != 0 || end != len(s) {
+			t.Errorf("scanObject(s, 0) returns %d, %d, %v", start, end, err)
+		}
+
+		if len(warnings) != 1 || warnings[0].Offset != 24 || warnings[0].Code != JsonErrorDuplicateKey {
+			t.Errorf("scanObject(s, 0) reports warnings %v", warnings)
+		}
+	}
+
+	{
+		sc := newJsonScanner(&Options{DuplicateKeys: DuplicateKeyWarn})
+		start, end, err := sc.scanObject(s, 0)
+		if err != nil || start != 0 || end != len(s) {
+			t.Errorf("scanObject(s, 0) returns %d, %d, %v", start, end, err)
+		}
+	}
+}
+
+func TestDuplicateKeyWarningsOnce(t *testing.T) {
+	// candidates may be scanned more than once, warnings are reported once for each offset
+	//           0        10        20        30
+	s := []byte(`{"a": 1, "a": 2} [3, {"b": 4} oops`)
+	warnings := make([]int, 0)
+	options := &Options{
+		DuplicateKeys: DuplicateKeyWarn,
+		OnWarning: func(w *JsonError) {
+			warnings = append(warnings, w.Offset)
+		},
+	}
+
+	check := func(name string) {
+		t.Helper()
+		if len(warnings) != 1 || warnings[0] != 9 {
+			t.Errorf("%s reports warnings at %v", name, warnings)
+		}
+
+		warnings = warnings[:0]
+	}
+
+	f := NewFinder(s, JsonValueAll, options)
+	f.SetMode(SearchResumeAfterStart)
+	finderCollect(f)
+	check("Finder in SearchResumeAfterStart")
+
+	options.Selection = SelectLargest
+	FindJsonWithOptions(s, 0, JsonValueAll, options)
+	check("FindJsonWithOptions in SelectLargest")
+
+	options.Selection = SelectFirst
+	options.MinItems = 3
+	FindJsonMatchWithOptions(s, 0, JsonValueAll, options)
+	check("FindJsonMatchWithOptions with MinItems")
+
+	options.MinItems = 0
+	lines := &LineOptions{Multiple: LineMultipleError}
+	r := NewLineReader(bytes.NewReader(s), JsonValueAll, lines, options)
+	for r.Next() {
+	}
+	check("LineReader in LineMultipleError")
+
+	// truncated value is scanned again to be repaired
+	s = []byte(`{"a": 1, "a": 2, "b": [1`)
+	RepairJson(s, 0, JsonValueObject, options)
+	check("RepairJson")
+}
+
+func TestScanJsonFeaturesOfStyle(t *testing.T) {
+	type styleCase struct {
+		s     string
+		style Style
+	}
+
+	caseList := []styleCase{
+		{`[1, 2,]`, StyleTrailingComma},
+		{`{"a": 1,}`, StyleTrailingComma},
+		{"[\"a\tb\"]", StyleControlChars},
+		{"[1, // one\n 2 /* two */, /**/ 3 // three\n]", StyleComments},
+		{`{/* empty */}`, StyleComments},
+		{`['abc', 'a"b', 'it\'s', "it\'s"]`, StyleSingleQuotes},
+		{`{'a': 1}`, StyleSingleQuotes},
+		{`{a: 1, $b_2: 2, _: 3}`, StyleUnquotedKeys},
+		{`{
+			// comment
+			key: 'value', 'other': [1, 2,],
+		}`, JavaScriptLiteralStyle},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		scanner := newJsonScannerWithStyle(c.style).getScanner(firstSetKind(s[0], c.style))
+		start, end, err := scanner(s, 0)
+		if err != nil || start != 0 || end != len(s) {
+			t.Errorf("scan %s in style %s returns %d, %d, %v", c.s, c.style, start, end, err)
+		}
+
+		start, end, err = FindJsonWithStyle(s, 0, JsonValueAll, NormativeStyle)
+		if err == nil && start == 0 && end == len(s) {
+			t.Errorf("scan %s in normative style returns %d, %d, nil", c.s, start, end)
+		}
+	}
+}
+
+func TestScanJsonFeaturesOfStyleFailure(t *testing.T) {
+	{
+		//           0         1         2
+		//           0123456789012345678901234
+		s := []byte(`[1, 2 /* unclosed ]`)
+		//                              ^
+		start, end, err := scanJsonArray(s, 0, JavaScriptLiteralStyle)
+		if err == nil {
+			t.Fatalf("scanJsonArray(s, 0, JavaScriptLiteralStyle) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 19: expect comma',' or bracket ']', got 'EOF'" {
+			t.Errorf("scanJsonArray(s, 0, JavaScriptLiteralStyle) returns %d, %d, %s", start, end, err)
+		}
+	}
+
+	{
+		//           0         1         2
+		//           0123456789012345678901234
+		s := []byte(`{'a': 'unclosed}`)
+		//                           ^
+		start, end, err := scanJsonObject(s, 0, JavaScriptLiteralStyle)
+		if err == nil {
+			t.Fatalf("scanJsonObject(s, 0, JavaScriptLiteralStyle) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 16: expect quote ''', got 'EOF'" {
+			t.Errorf("scanJsonObject(s, 0, JavaScriptLiteralStyle) returns %d, %d, %s", start, end, err)
+		}
+	}
+
+	{
+		s := []byte(`{1a: 1}`)
+		start, end, err := scanJsonObject(s, 0, JavaScriptLiteralStyle)
+		if err == nil {
+			t.Fatalf("scanJsonObject(s, 0, JavaScriptLiteralStyle) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 1: expect quote '\"', got '1'" {
+			t.Errorf("scanJsonObject(s, 0, JavaScriptLiteralStyle) returns %d, %d, %s", start, end, err)
+		}
+	}
+
+	{
+		// duplicate keys are compared after unquoting, whatever the quotes.
+		s := []byte(`{a: 1, 'a': 2}`)
+		sc := newJsonScanner(&Options{Style: JavaScriptLiteralStyle, DuplicateKeys: DuplicateKeyReject})
+		start, end, err := sc.scanObject(s, 0)
+		if err == nil {
+			t.Fatalf("scanObject(s, 0) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 7: duplicate key 'a' in object" {
+			t.Errorf("scanObject(s, 0) returns %d, %d, %s", start, end, err)
+		}
+	}
+}
+
+func TestScanPythonStyle(t *testing.T) {
+	scanner := GetScannerOf(JsonValueObject, PythonStyle)
+	caseList := scannerCorrectCases{
+		`{'a': True, 'b': None, 'c': (1, 2)}`,
+		`{'t': ((1, 2), [3, (4,)], ()), "f": False,}`,
+		`{'json': [true, false, null]}`,
+	}
+
+	caseList.On(t, scanner)
+
+	type failureCase struct {
+		s   string
+		msg string
+	}
+
+	failureList := []failureCase{
+		{`{'a': (1, 2]}`, "JSON error at 11: expect comma ',' or parenthesis ')', got ']'"},
+		{`{'a': (1, 2`, "JSON error at 11: expect comma ',' or parenthesis ')', got 'EOF'"},
+		{`{'a': (`, "JSON error at 7: expect value or parenthesis ')', got 'EOF'"},
+		{`{'a': Nope}`, "JSON error at 6: expect None, True or False, got 'Nope}'"},
+		{`{'a': TRUE}`, "JSON error at 6: expect None, True or False, got 'TRUE}'"},
+		{`{'a': [1, 2)}`, "JSON error at 11: expect comma ',' or bracket ']', got ')'"},
+	}
+
+	for _, c := range failureList {
+		s := []byte(c.s)
+		start, end, err := scanner(s, 0)
+		if err == nil {
+			t.Errorf("scan %s returns %d, %d, nil", c.s, start, end)
+			continue
+		}
+
+		if err.Error() != c.msg {
+			t.Errorf("scan %s returns %d, %d, %s", c.s, start, end, err)
+		}
+	}
+
+	{
+		s := []byte(`(1, 2)`)
+		start, end, err := scanJsonArrayJSS(s, 0)
+		if err == nil {
+			t.Fatalf("scanJsonArrayJSS(s, 0) returns %d, %d, nil", start, end)
+		}
+
+		if err.Error() != "JSON error at 0: expect bracket '[', got '('" {
+			t.Errorf("scanJsonArrayJSS(s, 0) returns %d, %d, %s", start, end, err)
+		}
+	}
+}
+
 func TestScanVeryDeepObjectJNS(t *testing.T) {
 	// original 1,000,000 levels nested object may cause stack overflow on go versions earlier than 1.15
 	depth := 900 * 1000
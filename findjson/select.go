@@ -0,0 +1,122 @@
+package findjson
+
+// Which value is returned when several candidates are found after the start offset.
+type SelectionPolicy int
+
+const (
+	// The first value found, candidates after it are not scanned.
+	SelectFirst = SelectionPolicy(0)
+
+	// The value of the largest span, the first one on tie, e.g. the object in `id 42 {"x":1}`.
+	SelectLargest = SelectionPolicy(1)
+
+	// The first array or object, scalars are skipped even if kind contains them. Values nested
+	// in a container found are never returned, unless it is skipped by thresholds in options.
+	SelectOutermost = SelectionPolicy(2)
+
+	// The value of the highest-ranked kind, the first one on tie. Kinds are ranked in order of
+	// object, array, string, number, boolean and null.
+	SelectHighestKind = SelectionPolicy(3)
+)
+
+// Check whether match a is preferred over match b, which is found earlier, in policy.
+func (policy SelectionPolicy) prefer(a Match, b Match) bool {
+	switch policy {
+	case SelectLargest:
+		return a.End-a.Start > b.End-b.Start
+
+	case SelectHighestKind:
+		// values of kinds are in order of rank
+		return a.Kind > b.Kind
+	}
+
+	return false
+}
+
+// Find value selected by selection policy in options, start from offset i.
+//
+// Values are found one by one as Finder does, from the end of the last one, values below
+// thresholds in options are skipped, and searched inside as Finder does. If none is found, the
+// first failed candidate is returned, or the failure that no candidate is found.
+func (sc *jsonScanner) findSelected(s []byte, i int, kind JsonValueKind) Match {
+	policy := sc.options.Selection
+	if policy == SelectFirst && !sc.hasThresholds() {
+		return sc.findMatch(s, i, kind)
+	}
+
+	top := JsonValueKind(0)
+	for k := JsonValueObject; k > 0; k >>= 1 {
+		if kind&k != 0 {
+			top = k
+			break
+		}
+	}
+
+	var failure *Match
+	var selected *Match
+	offset := i
+	for offset < len(s) {
+		m := sc.findMatch(s, offset, kind)
+		if m.Kind == 0 {
+			// no candidate found
+			if failure == nil {
+				failure = &m
+			}
+
+			break
+		}
+
+		if !m.Found() {
+			if failure == nil {
+				failure = &m
+			}
+
+			if m.Next() <= m.Start {
+				offset = m.Start + 1
+
+			} else {
+				offset = m.Next()
+			}
+
+			continue
+		}
+
+		if sc.isTrivial(m) {
+			offset = sc.skipTrivial(m)
+			continue
+		}
+
+		offset = m.End
+
+		if policy == SelectFirst {
+			return m
+		}
+
+		if policy == SelectOutermost {
+			if m.Kind == JsonValueArray || m.Kind == JsonValueObject {
+				return m
+			}
+
+			continue
+		}
+
+		if selected == nil || policy.prefer(m, *selected) {
+			selected = &m
+		}
+
+		if policy == SelectHighestKind && m.Kind == top {
+			// nothing ranks higher
+			break
+		}
+	}
+
+	if selected != nil {
+		return *selected
+	}
+
+	if failure != nil {
+		return *failure
+	}
+
+	return sc.findMatch(s, offset, kind)
+}
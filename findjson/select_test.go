@@ -0,0 +1,71 @@
+package findjson
+
+import (
+	"testing"
+)
+
+func TestFindJsonSelection(t *testing.T) {
+	type selectionCase struct {
+		s      string
+		kind   JsonValueKind
+		policy SelectionPolicy
+		exp    string
+	}
+
+	caseList := []selectionCase{
+		{`id 42 {"x":1}`, JsonValueAll, SelectFirst, `42`},
+		{`id 42 {"x":1}`, JsonValueAll, SelectLargest, `{"x":1}`},
+		{`id 42 {"x":1}`, JsonValueAll, SelectOutermost, `{"x":1}`},
+		{`id 42 {"x":1}`, JsonValueAll, SelectHighestKind, `{"x":1}`},
+		{`[1] "long string" {}`, JsonValueAll, SelectLargest, `"long string"`},
+		{`[1] "long string" {}`, JsonValueAll, SelectOutermost, `[1]`},
+		{`[1] "long string" {}`, JsonValueAll, SelectHighestKind, `{}`},
+		{`[1, 2] [3, 4] [5]`, JsonValueArray, SelectLargest, `[1, 2]`},
+		{`true 1 null "a"`, JsonValueAll, SelectHighestKind, `"a"`},
+		{`true 1 null`, JsonValueNull | JsonValueBoolean, SelectHighestKind, `true`},
+		{`{"a": [1, 2, 3, 4]} [5]`, JsonValueAll, SelectLargest, `{"a": [1, 2, 3, 4]}`},
+		{`{"a": } {"b": 1} {"c": [2, 3]}`, JsonValueObject, SelectLargest, `{"c": [2, 3]}`},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		options := &Options{Selection: c.policy}
+		start, end, err := FindJsonWithOptions(s, 0, c.kind, options)
+		if err != nil || string(s[start:end]) != c.exp {
+			t.Errorf("FindJsonWithOptions(%s) in policy %d returns %d, %d, %v", c.s, c.policy, start, end, err)
+		}
+
+		m := FindJsonMatchWithOptions(s, 0, c.kind, options)
+		if !m.Found() || string(m.Bytes()) != c.exp {
+			t.Errorf("FindJsonMatchWithOptions(%s) in policy %d returns %v", c.s, c.policy, m)
+		}
+	}
+}
+
+func TestFindJsonSelectionFailed(t *testing.T) {
+	//           0         1
+	//           01234567890123456
+	s := []byte(`{"a": } 1 "b" 2`)
+	start, end, err := FindJsonWithOptions(s, 0, JsonValueObject, &Options{Selection: SelectLargest})
+	if err == nil || start != 0 || end != 6 {
+		t.Errorf("FindJsonWithOptions(%s) returns %d, %d, %v", s, start, end, err)
+	}
+
+	// scalars are skipped in SelectOutermost
+	m := FindJsonMatchWithOptions(s, 0, JsonValueAll, &Options{Selection: SelectOutermost})
+	if m.Found() || m.Start != 0 || m.Err == nil {
+		t.Errorf("FindJsonMatchWithOptions(%s) returns %v", s, m)
+	}
+
+	s = []byte(`1 2 3`)
+	m = FindJsonMatchWithOptions(s, 0, JsonValueAll, &Options{Selection: SelectOutermost})
+	if m.Found() || m.Kind != 0 || m.Err.Error() != "JSON error at 5: no JSON string found in null|boolean|number|string|array|object" {
+		t.Errorf("FindJsonMatchWithOptions(%s) returns %v", s, m)
+	}
+
+	s = []byte(`no json`)
+	m = FindJsonMatchWithOptions(s, 0, JsonValueObject, &Options{Selection: SelectLargest})
+	if m.Found() || m.Kind != 0 {
+		t.Errorf("FindJsonMatchWithOptions(%s) returns %v", s, m)
+	}
+}
@@ -0,0 +1,112 @@
+package findjson
+
+import (
+	"strings"
+)
+
+// Grammar style, a set of grammar features beyond RFC 8259, can be used as a bitmask.
+//
+// NormativeStyle, JavaScriptStyle, JavaScriptLiteralStyle and PythonStyle are presets, other
+// combinations of features are also accepted.
+type Style int
+
+// Features and presets are untyped constants, which can be passed to functions taking int
+// styles, e.g. FindJsonWithStyle, as well as assigned to Style. Features start from 2, 1 is
+// JavaScriptStyle as it was before features.
+const (
+	StyleTrailingComma  = 2   // trailing comma in arrays and objects, e.g. [1, 2,]
+	StyleControlChars   = 4   // raw control chars in strings
+	StyleComments       = 8   // comments as white spaces, e.g. // line, /* block */
+	StyleSingleQuotes   = 16  // single-quoted strings, e.g. 'string'
+	StyleUnquotedKeys   = 32  // identifiers as object keys, e.g. {key: 1}
+	StylePythonLiterals = 64  // Python literals True, False and None
+	StyleTuples         = 128 // Python tuples as arrays, e.g. (1, 2)
+	StyleSpecialNumbers = 256 // NaN, Infinity, hex and leading-dot floats, see NumberForm
+
+	styleAllFeatures = StyleTrailingComma | StyleControlChars | StyleComments |
+		StyleSingleQuotes | StyleUnquotedKeys | StylePythonLiterals | StyleTuples |
+		StyleSpecialNumbers
+)
+
+// Preset grammar styles.
+//
+// NormativeStyle follows RFC 8259 strictly.
+// JavaScriptStyle accepts trailing commas and raw control chars in strings, as it did before
+// features, it can be combined with other features.
+// JavaScriptLiteralStyle accepts JavaScript object and array literals.
+// PythonStyle accepts repr() output of Python dicts, lists and tuples, JSON literals true,
+// false and null are also accepted.
+const (
+	NormativeStyle         = 0
+	JavaScriptStyle        = 1
+	JavaScriptLiteralStyle = StyleTrailingComma | StyleControlChars | StyleComments |
+		StyleSingleQuotes | StyleUnquotedKeys
+	PythonStyle = StyleTrailingComma | StyleSingleQuotes | StylePythonLiterals | StyleTuples
+)
+
+// Get features of style, with JavaScriptStyle expanded to its features.
+func (s Style) features() Style {
+	if s&JavaScriptStyle != 0 {
+		return s&^JavaScriptStyle | StyleTrailingComma | StyleControlChars
+	}
+
+	return s
+}
+
+// Check whether style is made of presets and known features only.
+func (s Style) IsValid() bool {
+	return s&^(styleAllFeatures|JavaScriptStyle) == 0
+}
+
+// Check whether all features in f are enabled.
+func (s Style) Has(f Style) bool {
+	f = f.features()
+	return s.features()&f == f
+}
+
+func (s Style) String() string {
+	if s == NormativeStyle {
+		return "normative"
+	}
+
+	s = s.features()
+
+	names := make([]string, 0, 8)
+	if s&StyleTrailingComma != 0 {
+		names = append(names, "trailing-comma")
+	}
+
+	if s&StyleControlChars != 0 {
+		names = append(names, "control-chars")
+	}
+
+	if s&StyleComments != 0 {
+		names = append(names, "comments")
+	}
+
+	if s&StyleSingleQuotes != 0 {
+		names = append(names, "single-quotes")
+	}
+
+	if s&StyleUnquotedKeys != 0 {
+		names = append(names, "unquoted-keys")
+	}
+
+	if s&StylePythonLiterals != 0 {
+		names = append(names, "python-literals")
+	}
+
+	if s&StyleTuples != 0 {
+		names = append(names, "tuples")
+	}
+
+	if s&StyleSpecialNumbers != 0 {
+		names = append(names, "special-numbers")
+	}
+
+	if !s.IsValid() {
+		names = append(names, "unknown")
+	}
+
+	return strings.Join(names, "|")
+}
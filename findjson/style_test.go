@@ -0,0 +1,79 @@
+package findjson
+
+import (
+	"testing"
+)
+
+func TestStyleIsValid(t *testing.T) {
+	if !Style(NormativeStyle).IsValid() {
+		t.Errorf("NormativeStyle is not valid")
+	}
+
+	if !Style(JavaScriptLiteralStyle).IsValid() {
+		t.Errorf("JavaScriptLiteralStyle is not valid")
+	}
+
+	if s := Style(StyleTrailingComma | StyleComments); !s.IsValid() {
+		t.Errorf("%s is not valid", s)
+	}
+
+	if !Style(PythonStyle).IsValid() {
+		t.Errorf("PythonStyle is not valid")
+	}
+
+	if s := Style(-1); s.IsValid() {
+		t.Errorf("Style(-1) is valid")
+	}
+}
+
+func TestStyleHas(t *testing.T) {
+	s := Style(StyleTrailingComma | StyleComments)
+	if !s.Has(StyleComments) {
+		t.Errorf("%s has no comments", s)
+	}
+
+	if s.Has(StyleComments | StyleSingleQuotes) {
+		t.Errorf("%s has single quotes", s)
+	}
+
+	if !Style(JavaScriptLiteralStyle).Has(s) {
+		t.Errorf("JavaScriptLiteralStyle has no %s", s)
+	}
+
+	// JavaScriptStyle is 1 as it was, with its features
+	s = Style(JavaScriptStyle)
+	if !s.IsValid() || !s.Has(StyleTrailingComma|StyleControlChars) || s.Has(StyleComments) {
+		t.Errorf("JavaScriptStyle is %s", s)
+	}
+
+	if !Style(JavaScriptLiteralStyle).Has(JavaScriptStyle) {
+		t.Errorf("JavaScriptLiteralStyle has no JavaScriptStyle")
+	}
+}
+
+func TestStyleString(t *testing.T) {
+	if v := Style(NormativeStyle).String(); v != "normative" {
+		t.Errorf("Style(NormativeStyle).String() returns '%s'", v)
+	}
+
+	exp := "trailing-comma|control-chars|comments|single-quotes|unquoted-keys"
+	if v := Style(JavaScriptLiteralStyle).String(); v != exp {
+		t.Errorf("Style(JavaScriptLiteralStyle).String() returns '%s'", v)
+	}
+
+	if v := Style(JavaScriptStyle).String(); v != "trailing-comma|control-chars" {
+		t.Errorf("Style(JavaScriptStyle).String() returns '%s'", v)
+	}
+
+	if v := Style(PythonStyle).String(); v != "trailing-comma|single-quotes|python-literals|tuples" {
+		t.Errorf("Style(PythonStyle).String() returns '%s'", v)
+	}
+
+	if v := Style(StyleSpecialNumbers).String(); v != "special-numbers" {
+		t.Errorf("StyleSpecialNumbers.String() returns '%s'", v)
+	}
+
+	if v := Style(StyleComments | 0x4000).String(); v != "comments|unknown" {
+		t.Errorf("Style(0x4008).String() returns '%s'", v)
+	}
+}
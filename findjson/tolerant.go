@@ -0,0 +1,511 @@
+package findjson
+
+import (
+	"math/big"
+	"sort"
+	"unicode/utf8"
+)
+
+// Result of tolerant parsing, see ParseJsonTolerant.
+type TolerantResult struct {
+	Start int           // start of value
+	End   int           // end of value, exclusive, as far as the value is salvaged
+	Kind  JsonValueKind // kind of value
+
+	// Errors recovered from, in order of offset. Empty if the value is accepted in the style of
+	// options as is.
+	Errors []*JsonError
+
+	// Lossy conversions in Json, in order of offset, e.g. NaN and Infinity rendered as null.
+	// They are not errors if the value is accepted in the style of options.
+	Warnings []*JsonError
+
+	// Value rendered in strict JSON of RFC 8259, without white spaces. Non-standard forms are
+	// converted, e.g. single-quoted strings, unquoted keys, tuples, Python literals, hex numbers,
+	// and comments and trailing commas are dropped.
+	Json []byte
+}
+
+// Check whether the value is accepted without any error.
+func (r *TolerantResult) Clean() bool {
+	return len(r.Errors) == 0
+}
+
+// Parser which continues past errors in arrays and objects.
+type tolerantParser struct {
+	strict *jsonScanner // scanner of style in options, errors are recorded against it
+	loose  *jsonScanner // scanner with all features, for salvaging scalars
+	s      []byte
+	out    []byte
+	errors []*JsonError
+	warns  []*JsonError
+	depth  int
+	fatal  *JsonError // error which stops parsing, e.g. too deep
+}
+
+func (p *tolerantParser) fail(offset int, message string, args ...interface{}) {
+	p.errors = append(p.errors, NewJsonError(offset, message, args...))
+}
+
+func (p *tolerantParser) failWith(err error, offset int) {
+	p.errors = append(p.errors, toJsonError(err, offset))
+}
+
+// Skip white spaces and comments, comments are errors if not allowed.
+func (p *tolerantParser) skipSpace(j int) int {
+	s := p.s
+	l := len(s)
+	for {
+		j = jumpNextNonWhiteSpace(s, j)
+		if j+1 >= l || s[j] != jsonSlash || (s[j+1] != jsonSlash && s[j+1] != jsonAsterisk) {
+			return j
+		}
+
+		if !p.strict.allow(StyleComments) {
+			p.fail(j, "unexpected comment")
+		}
+
+		end, closed := commentEnd(s, j)
+		if !closed && s[j+1] == jsonAsterisk {
+			p.fail(l, "comment is not closed, got 'EOF'")
+		}
+
+		j = end
+	}
+}
+
+// Skip an unexpected token at s[j], at least one char.
+func (p *tolerantParser) skipToken(j int) int {
+	v := bufferFindSample(p.s, j, 1)
+	p.fail(j, "unexpected char '%s'", v)
+
+	k := j + 1
+	if isIdentifierPart(p.s[j]) {
+		for k < len(p.s) && !isRepairDelimiter(p.s[k]) {
+			k++
+		}
+	}
+
+	return k
+}
+
+// Write JSON string of decoded content v.
+func appendJsonQuoted(dst []byte, v []byte) []byte {
+	const hex = "0123456789abcdef"
+	dst = append(dst, jsonQuote)
+	for i := 0; i < len(v); {
+		c := v[i]
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRune(v[i:])
+			if r == utf8.RuneError && size == 1 {
+				dst = append(dst, "\ufffd"...)
+
+			} else {
+				dst = append(dst, v[i:i+size]...)
+			}
+
+			i += size
+			continue
+		}
+
+		switch {
+		case c == jsonQuote || c == jsonBackslash:
+			dst = append(dst, jsonBackslash, c)
+
+		case c == '\n':
+			dst = append(dst, `\n`...)
+
+		case c == '\r':
+			dst = append(dst, `\r`...)
+
+		case c == '\t':
+			dst = append(dst, `\t`...)
+
+		case c < 0x20 || c == 0x7f:
+			dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+
+		default:
+			dst = append(dst, c)
+		}
+
+		i++
+	}
+
+	return append(dst, jsonQuote)
+}
+
+// Render scalar s[start:end] in strict JSON, as key in objects if asKey.
+func (p *tolerantParser) renderScalar(start int, end int, asKey bool) {
+	s := p.s
+	c := s[start]
+	if c == jsonQuote || c == jsonSingleQuote {
+		v, err := UnquoteString(s, start, end)
+		if err != nil {
+			p.failWith(err, start)
+		}
+
+		p.out = appendJsonQuoted(p.out, v)
+		return
+	}
+
+	text := s[start:end]
+	if asKey {
+		p.out = appendJsonQuoted(p.out, text)
+		return
+	}
+
+	switch string(text) {
+	case "true", "True":
+		p.out = append(p.out, jsonLiteralTrue...)
+		return
+
+	case "false", "False":
+		p.out = append(p.out, jsonLiteralFalse...)
+		return
+
+	case "null", "None":
+		p.out = append(p.out, jsonLiteralNull...)
+		return
+	}
+
+	negative := c == jsonSignNegative
+	digits := text
+	if negative {
+		digits = text[1:]
+	}
+
+	switch GetNumberForm(s, start, end) {
+	case NumberFormNaN, NumberFormInfinity:
+		p.warns = append(p.warns, NewJsonError(start, "%s is not a number in JSON, rendered as null", text))
+		p.out = append(p.out, jsonLiteralNull...)
+
+	case NumberFormHex:
+		n, _ := new(big.Int).SetString(string(digits[2:]), 16)
+		if negative {
+			n.Neg(n)
+		}
+
+		p.out = n.Append(p.out, 10)
+
+	case NumberFormLeadingDot:
+		if negative {
+			p.out = append(p.out, jsonSignNegative)
+		}
+
+		p.out = append(p.out, jsonDigitZero)
+		p.out = append(p.out, digits...)
+
+	default:
+		p.out = append(p.out, text...)
+	}
+}
+
+// Parse scalar at s[j], scalars not accepted in style are salvaged with all features.
+// Returns end of scalar, and whether it is rendered.
+func (p *tolerantParser) parseScalar(j int, asKey bool) (int, bool) {
+	s := p.s
+	scalars := JsonValueNull | JsonValueBoolean | JsonValueNumber | JsonValueString
+	_, end, err := p.strict.scanValue(s, j, scalars)
+	_, looseEnd, looseErr := p.loose.scanValue(s, j, scalars)
+	if looseErr != nil {
+		if err == nil {
+			p.renderScalar(j, end, asKey)
+			return end, true
+		}
+
+		if c := s[j]; c == jsonQuote || c == jsonSingleQuote {
+			// broken string, e.g. "a\x", skipped as a whole
+			p.failWith(looseErr, looseEnd)
+			if end := repairStringEnd(s, j); end > 0 {
+				return end, false
+			}
+
+			return len(s), false
+		}
+
+		if looseEnd > j {
+			// broken number, e.g. 1.e5, skipped to next delimiter
+			p.failWith(looseErr, looseEnd)
+			for looseEnd < len(s) && !isRepairDelimiter(s[looseEnd]) {
+				looseEnd++
+			}
+
+			return looseEnd, false
+		}
+
+		return p.skipToken(j), false
+	}
+
+	if err != nil {
+		p.failWith(err, j)
+
+	} else if end < looseEnd {
+		// prefix accepted in style, e.g. 0 of 0x1F
+		v := bufferFindSample(s, end, 1)
+		p.fail(end, "unexpected char '%s'", v)
+	}
+
+	p.renderScalar(j, looseEnd, asKey)
+	return looseEnd, true
+}
+
+// Parse value at s[j], returns end of value, and whether it is rendered.
+func (p *tolerantParser) parseValue(j int) (int, bool) {
+	switch c := p.s[j]; {
+	case c == jsonLBracket:
+		return p.parseContainer(j, jsonRBracket, p.parseValue)
+
+	case c == jsonLParen:
+		if !p.strict.allow(StyleTuples) {
+			p.fail(j, "unexpected tuple")
+		}
+
+		return p.parseContainer(j, jsonRParen, p.parseValue)
+
+	case c == jsonLBrace:
+		return p.parseContainer(j, jsonRBrace, p.parseMember)
+	}
+
+	return p.parseScalar(j, false)
+}
+
+func (p *tolerantParser) enter(j int) *JsonError {
+	p.depth++
+	if max := p.strict.options.MaxDepth; max > 0 && p.depth > max {
+		return NewJsonErrorWithCode(JsonErrorTooDeep, j, "nesting is deeper than %d", max)
+	}
+
+	return nil
+}
+
+func isCloser(c byte) bool {
+	return c == jsonRBracket || c == jsonRBrace || c == jsonRParen
+}
+
+// Check end of container at s[j], returns end of container.
+func (p *tolerantParser) closeContainer(j int, closer byte, comma int) int {
+	p.depth--
+	s := p.s
+	if comma >= 0 && s[comma] == jsonComma && !p.strict.allow(StyleTrailingComma) {
+		p.fail(comma, "unexpected trailing comma")
+	}
+
+	if j >= len(s) {
+		p.fail(j, "expect '%c', got 'EOF'", closer)
+		return j
+	}
+
+	if s[j] != closer {
+		// closer of outer container, closes this one too
+		p.fail(j, "expect '%c', got '%c'", closer, s[j])
+		return j
+	}
+
+	return j + 1
+}
+
+// Parse array, tuple or object at s[i], which ends with closer, items are parsed with parseItem.
+// Items which can not be salvaged are dropped, containers are always rendered.
+func (p *tolerantParser) parseContainer(i int, closer byte, parseItem func(j int) (int, bool)) (int, bool) {
+	if p.fatal = p.enter(i); p.fatal != nil {
+		return i, false
+	}
+
+	s := p.s
+	l := len(s)
+	opener, rendered := byte(jsonLBracket), byte(jsonRBracket)
+	if closer == jsonRBrace {
+		opener, rendered = jsonLBrace, jsonRBrace
+	}
+
+	p.out = append(p.out, opener)
+	seen := 0     // items parsed, rendered or not
+	count := 0    // items rendered
+	comma := -1   // offset of comma after the last item, -1 if none
+	junk := false // whether the last item is dropped, its error is reported
+	j := i + 1
+	for {
+		j = p.skipSpace(j)
+		if j >= l || isCloser(s[j]) {
+			break
+		}
+
+		if s[j] == jsonComma || (s[j] == ';' && seen > 0 && comma < 0) {
+			// a stray ';' after an item is taken as comma, with one error
+			if s[j] != jsonComma {
+				p.fail(j, "expect comma ',', got ';'")
+
+			} else if seen == 0 || comma >= 0 {
+				p.fail(j, "unexpected comma ','")
+			}
+
+			comma = j
+			j++
+			continue
+		}
+
+		mark := len(p.out)
+		errMark := len(p.errors)
+		if count > 0 {
+			p.out = append(p.out, jsonComma)
+		}
+
+		end, ok := parseItem(j)
+		if p.fatal != nil {
+			return end, false
+		}
+
+		if ok && seen > 0 && comma < 0 && !junk {
+			// missing comma is reported only between items salvaged, not around junk
+			v := bufferFindSample(s, j, 1)
+			p.errors = append(p.errors, nil)
+			copy(p.errors[errMark+1:], p.errors[errMark:])
+			p.errors[errMark] = NewJsonError(j, "expect comma ',', got '%s'", v)
+		}
+
+		if ok {
+			count++
+
+		} else {
+			p.out = p.out[:mark]
+		}
+
+		seen++
+		junk = !ok
+		comma = -1
+		j = end
+	}
+
+	end := p.closeContainer(j, closer, comma)
+	p.out = append(p.out, rendered)
+	return end, true
+}
+
+// Parse member of object at s[j], returns end of member, and whether it is rendered.
+func (p *tolerantParser) parseMember(j int) (int, bool) {
+	s := p.s
+	l := len(s)
+	mark := len(p.out)
+	keep := true
+	if c := s[j]; isIdentifierStart(c) {
+		end := scanIdentifier(s, j)
+		if !p.strict.allow(StyleUnquotedKeys) {
+			p.fail(j, "unexpected unquoted key '%s'", s[j:end])
+		}
+
+		p.out = appendJsonQuoted(p.out, s[j:end])
+		j = end
+
+	} else if c == jsonLBracket || c == jsonLBrace || c == jsonLParen {
+		// parsed and dropped with its value
+		p.fail(j, "unexpected %s as key", firstSetKind(c, styleAllFeatures))
+		j, _ = p.parseValue(j)
+		if p.fatal != nil {
+			return j, false
+		}
+
+		keep = false
+
+	} else {
+		if c != jsonQuote && c != jsonSingleQuote && firstSetKind(c, styleAllFeatures) != 0 {
+			p.fail(j, "expect string as key, got '%c'", c)
+		}
+
+		var ok bool
+		if j, ok = p.parseScalar(j, true); !ok {
+			return j, false
+		}
+	}
+
+	j = p.skipSpace(j)
+	if j < l && s[j] == jsonColon {
+		j = p.skipSpace(j + 1)
+
+	} else {
+		v := bufferFindSample(s, j, 1)
+		p.fail(j, "expect colon ':', got '%s'", v)
+	}
+
+	if j >= l || isCloser(s[j]) || s[j] == jsonComma {
+		v := bufferFindSample(s, j, 1)
+		p.fail(j, "expect value, got '%s'", v)
+		return j, false
+	}
+
+	p.out = append(p.out, jsonColon)
+	end, ok := p.parseValue(j)
+	if !keep {
+		p.out = p.out[:mark]
+	}
+
+	return end, ok && keep
+}
+
+// Parse JSON value in s tolerantly, start from offset i, with options, nil for default. The
+// first candidate found is parsed, e.g. almost-JSON with missing commas, unquoted keys and stray
+// semicolons.
+//
+// Errors in arrays and objects are recovered from: members and elements which can not be
+// salvaged are dropped, missing commas and colons are assumed, containers closed by an outer
+// closer or EOF are closed. Features not in style are accepted, and reported as errors. Returns
+// the span, all errors with offsets, and the value rendered in strict JSON.
+//
+// Errors which can not be recovered from are returned, e.g. the root value is broken, or the
+// nesting is deeper than options.MaxDepth.
+func ParseJsonTolerant(s []byte, i int, kind JsonValueKind, options *Options) (*TolerantResult, error) {
+	strict := newJsonScanner(options)
+	style := strict.style
+	if !style.IsValid() {
+		return nil, NewJsonError(i, "unknown style %d", style)
+	}
+
+	looseOptions := *strict.options
+	looseOptions.Style = styleAllFeatures
+	p := &tolerantParser{
+		strict: strict,
+		loose:  newJsonScanner(&looseOptions),
+		s:      s,
+	}
+
+	p.loose.warned = strict.warned
+
+	l := len(s)
+	j := i
+	for j < l && kind&firstSetKind(s[j], style) == 0 {
+		j++
+	}
+
+	if j >= l {
+		return nil, NewJsonError(l, "no JSON string found in %s", kind)
+	}
+
+	k := kind & firstSetKind(s[j], style)
+	if k == JsonValueNull|JsonValueNumber {
+		k = kindOfCapitalN(s, j)
+	}
+
+	end, ok := p.parseValue(j)
+	if p.fatal != nil {
+		return nil, p.fatal
+	}
+
+	if !ok {
+		return nil, p.errors[len(p.errors)-1]
+	}
+
+	// errors of trailing commas are found at the end of containers
+	sort.SliceStable(p.errors, func(a int, b int) bool {
+		return p.errors[a].Offset < p.errors[b].Offset
+	})
+
+	result := &TolerantResult{
+		Start:    j,
+		End:      end,
+		Kind:     k,
+		Errors:   p.errors,
+		Warnings: p.warns,
+		Json:     p.out,
+	}
+
+	return result, nil
+}
@@ -0,0 +1,144 @@
+package findjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestParseJsonTolerant(t *testing.T) {
+	type tolerantCase struct {
+		s       string
+		style   Style
+		exp     string
+		offsets []int
+	}
+
+	caseList := []tolerantCase{
+		{`{"a": 1, "b": [true, null]}`, NormativeStyle, `{"a":1,"b":[true,null]}`, []int{}},
+		{`{"a": 1 "b": 2}`, NormativeStyle, `{"a":1,"b":2}`, []int{8}},
+		{`{a: 1, b: 'x'};`, NormativeStyle, `{"a":1,"b":"x"}`, []int{1, 7, 10}},
+		{`{a: 1, b: 'x'};`, JavaScriptLiteralStyle, `{"a":1,"b":"x"}`, []int{}},
+		{`[1 2; 3,,4,]`, NormativeStyle, `[1,2,3,4]`, []int{3, 4, 8, 10}},
+		{`{"a":1;"b":2}`, NormativeStyle, `{"a":1,"b":2}`, []int{6}},
+		{`[1;]`, PythonStyle, `[1]`, []int{2}},
+		{`[;1]`, NormativeStyle, `[1]`, []int{1}},
+		{`{"a" 1, "b": , "c": [1, 2}`, NormativeStyle, `{"a":1,"c":[1,2]}`, []int{5, 13, 25}},
+		{`[1, 2`, NormativeStyle, `[1,2]`, []int{5}},
+		{`[x, 1]`, NormativeStyle, `[1]`, []int{1}},
+		{`[1.e5, 2]`, NormativeStyle, `[2]`, []int{3}},
+		{`[1, /* c */ 2]`, NormativeStyle, `[1,2]`, []int{4}},
+		{`[1, /* c */ 2]`, StyleComments, `[1,2]`, []int{}},
+		{`{"a": "b\x", "c": 1}`, NormativeStyle, `{"c":1}`, []int{9}},
+		{"[\"a\tb\"]", NormativeStyle, `["a\tb"]`, []int{3}},
+		{`{1: 2, [3]: 4, "x": 5}`, NormativeStyle, `{"1":2,"x":5}`, []int{1, 7}},
+		{`{'a': (1, True, None)}`, PythonStyle, `{"a":[1,true,null]}`, []int{}},
+		{`[0x1F, .5, NaN, -Infinity]`, StyleSpecialNumbers, `[31,0.5,null,null]`, []int{}},
+		{`[0x1F]`, NormativeStyle, `[31]`, []int{2}},
+	}
+
+	for _, c := range caseList {
+		r, err := ParseJsonTolerant([]byte(c.s), 0, JsonValueAll, &Options{Style: c.style})
+		if err != nil || string(r.Json) != c.exp {
+			t.Errorf("ParseJsonTolerant(%s) returns %v, %v", c.s, r, err)
+			continue
+		}
+
+		if !json.Valid(r.Json) {
+			t.Errorf("ParseJsonTolerant(%s) renders invalid JSON %s", c.s, r.Json)
+		}
+
+		if r.Clean() != (len(c.offsets) == 0) || len(r.Errors) != len(c.offsets) {
+			t.Errorf("ParseJsonTolerant(%s) returns errors %v", c.s, r.Errors)
+			continue
+		}
+
+		for i, e := range r.Errors {
+			if e.Offset != c.offsets[i] {
+				t.Errorf("ParseJsonTolerant(%s) error[%d] is %v, expected at %d", c.s, i, e, c.offsets[i])
+			}
+		}
+	}
+}
+
+func TestParseJsonTolerantWarnings(t *testing.T) {
+	// NaN and Infinity are accepted in PythonStyle with special numbers, but rendered as null
+	s := []byte(`{'a': NaN, 'b': [1, -Infinity]}`)
+	r, err := ParseJsonTolerant(s, 0, JsonValueAll, &Options{Style: PythonStyle | StyleSpecialNumbers})
+	if err != nil || string(r.Json) != `{"a":null,"b":[1,null]}` || !r.Clean() {
+		t.Fatalf("ParseJsonTolerant(%s) returns %v, %v", s, r, err)
+	}
+
+	if len(r.Warnings) != 2 || r.Warnings[0].Offset != 6 || r.Warnings[1].Offset != 20 {
+		t.Errorf("ParseJsonTolerant(%s) returns warnings %v", s, r.Warnings)
+	}
+
+	// not accepted in NormativeStyle, an error and a warning
+	s = []byte(`[NaN]`)
+	r, err = ParseJsonTolerant(s, 0, JsonValueAll, nil)
+	if err != nil || string(r.Json) != `[null]` || len(r.Errors) != 1 || len(r.Warnings) != 1 {
+		t.Errorf("ParseJsonTolerant(%s) returns %v, %v", s, r, err)
+	}
+}
+
+func TestParseJsonTolerantSpan(t *testing.T) {
+	//           0         1         2
+	//           0123456789012345678901234
+	s := []byte(`log: {"a": 1 "b": [2]} tail`)
+	r, err := ParseJsonTolerant(s, 0, JsonValueObject, nil)
+	if err != nil || r.Start != 5 || r.End != 22 || r.Kind != JsonValueObject {
+		t.Fatalf("ParseJsonTolerant(%s) returns %v, %v", s, r, err)
+	}
+
+	// unclosed container ends at EOF
+	s = []byte(`[1, {"a": 2`)
+	r, err = ParseJsonTolerant(s, 0, JsonValueArray, nil)
+	if err != nil || r.End != len(s) || string(r.Json) != `[1,{"a":2}]` || len(r.Errors) != 2 {
+		t.Errorf("ParseJsonTolerant(%s) returns %v, %v", s, r, err)
+	}
+}
+
+func TestParseJsonTolerantFailed(t *testing.T) {
+	inputs := []string{
+		`x`,
+		`no json`,
+		`"unclosed`,
+	}
+
+	for _, v := range inputs {
+		if r, err := ParseJsonTolerant([]byte(v), 0, JsonValueAll, nil); err == nil {
+			t.Errorf("ParseJsonTolerant(%s) returns %v", v, r)
+		}
+	}
+
+	s := []byte(`[[[[1]]]]`)
+	_, err := ParseJsonTolerant(s, 0, JsonValueAll, &Options{MaxDepth: 3})
+	if e, ok := err.(*JsonError); !ok || e.Code != JsonErrorTooDeep {
+		t.Errorf("ParseJsonTolerant(%s) with MaxDepth returns %v", s, err)
+	}
+
+	if _, err := ParseJsonTolerant(s, 0, JsonValueAll, &Options{Style: Style(1 << 20)}); err == nil {
+		t.Errorf("ParseJsonTolerant() with unknown style returns nil error")
+	}
+}
+
+func ExampleParseJsonTolerant() {
+	s := []byte(`{id: 1 "tags": ["a", "b",]};`)
+
+	r, err := ParseJsonTolerant(s, 0, JsonValueObject, nil)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println(string(r.Json))
+	for _, e := range r.Errors {
+		fmt.Println(e)
+	}
+
+	// Output:
+	// {"id":1,"tags":["a","b"]}
+	// JSON error at 1: unexpected unquoted key 'id'
+	// JSON error at 7: expect comma ',', got '"'
+	// JSON error at 24: unexpected trailing comma
+}
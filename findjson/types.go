@@ -65,7 +65,7 @@ func GetScannerInJSS(kind JsonValueKind) JsonTokenScanner {
 		return scanJsonNumber
 
 	case JsonValueString:
-		return scanJsonString
+		return scanJsonStringJSS
 
 	case JsonValueArray:
 		return scanJsonArrayJSS
@@ -78,7 +78,14 @@ func GetScannerInJSS(kind JsonValueKind) JsonTokenScanner {
 	return nil
 }
 
+// Get scanner of kind in style, a preset or a set of features, see Style. Returns nil if kind
+// is not a single kind or style is not valid.
 func GetScannerOf(kind JsonValueKind, style int) JsonTokenScanner {
+	return GetScannerOfStyle(kind, Style(style))
+}
+
+// Get scanner of kind in style, nil if kind is not a single kind or style is not valid.
+func GetScannerOfStyle(kind JsonValueKind, style Style) JsonTokenScanner {
 	switch style {
 	case NormativeStyle:
 		return GetScannerInJNS(kind)
@@ -87,6 +94,10 @@ func GetScannerOf(kind JsonValueKind, style int) JsonTokenScanner {
 		return GetScannerInJSS(kind)
 	}
 
+	if style.IsValid() {
+		return newJsonScannerWithStyle(style).getScanner(kind)
+	}
+
 	return nil
 }
 
@@ -127,27 +138,146 @@ func (k JsonValueKind) CanScan(kind JsonValueKind, style int) JsonTokenScanner {
 	return nil
 }
 
-func (k JsonValueKind) GetScanner(c byte, style int) JsonTokenScanner {
+// Get the only kind of value which may start with char c, in FIRST SET, 0 if none.
+// 'N' may start both None and NaN, if both StylePythonLiterals and StyleSpecialNumbers are
+// enabled, use kindOfCapitalN to tell them apart.
+func firstSetKind(c byte, style Style) JsonValueKind {
 	switch c {
 	case 'n':
-		return k.CanScan(JsonValueNull, style)
+		return JsonValueNull
 
 	case 't', 'f':
-		return k.CanScan(JsonValueBoolean, style)
+		return JsonValueBoolean
 
 	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9', '-':
-		return k.CanScan(JsonValueNumber, style)
+		return JsonValueNumber
 
 	case jsonQuote:
-		return k.CanScan(JsonValueString, style)
+		return JsonValueString
+
+	case jsonSingleQuote:
+		if style&StyleSingleQuotes != 0 {
+			return JsonValueString
+		}
+
+	case 'N':
+		// None of Python, or NaN
+		var kind JsonValueKind
+		if style&StylePythonLiterals != 0 {
+			kind |= JsonValueNull
+		}
+
+		if style&StyleSpecialNumbers != 0 {
+			kind |= JsonValueNumber
+		}
+
+		return kind
+
+	case 'I', jsonPeriod:
+		// Infinity, or float without leading digit
+		if style&StyleSpecialNumbers != 0 {
+			return JsonValueNumber
+		}
+
+	case 'T', 'F':
+		if style&StylePythonLiterals != 0 {
+			return JsonValueBoolean
+		}
+
+	case jsonLParen:
+		if style&StyleTuples != 0 {
+			return JsonValueArray
+		}
 
 	case jsonLBracket:
-		return k.CanScan(JsonValueArray, style)
+		return JsonValueArray
 
 	case jsonLBrace:
-		return k.CanScan(JsonValueObject, style)
+		return JsonValueObject
+	}
+
+	return 0
+}
+
+// Get all chars in FIRST SET of kind in style, which agrees with firstSetKind.
+func firstSetChars(kind JsonValueKind, style Style) string {
+	chars := make([]byte, 0, 24)
+	if kind&JsonValueNull != 0 {
+		chars = append(chars, 'n')
+		if style&StylePythonLiterals != 0 {
+			chars = append(chars, 'N')
+		}
+	}
+
+	if kind&JsonValueBoolean != 0 {
+		chars = append(chars, 't', 'f')
+		if style&StylePythonLiterals != 0 {
+			chars = append(chars, 'T', 'F')
+		}
+	}
+
+	if kind&JsonValueNumber != 0 {
+		chars = append(chars, "0123456789-"...)
+		if style&StyleSpecialNumbers != 0 {
+			chars = append(chars, 'N', 'I', jsonPeriod)
+		}
+	}
+
+	if kind&JsonValueString != 0 {
+		chars = append(chars, jsonQuote)
+		if style&StyleSingleQuotes != 0 {
+			chars = append(chars, jsonSingleQuote)
+		}
+	}
+
+	if kind&JsonValueArray != 0 {
+		chars = append(chars, jsonLBracket)
+		if style&StyleTuples != 0 {
+			chars = append(chars, jsonLParen)
+		}
+	}
+
+	if kind&JsonValueObject != 0 {
+		chars = append(chars, jsonLBrace)
+	}
+
+	return string(chars)
+}
+
+// Get kind of value starts with 'N' at s[i], null for None and number for NaN.
+func kindOfCapitalN(s []byte, i int) JsonValueKind {
+	if i+1 < len(s) && s[i+1] == 'a' {
+		return JsonValueNumber
+	}
+
+	return JsonValueNull
+}
+
+func (k JsonValueKind) GetScanner(c byte, style int) JsonTokenScanner {
+	kind := firstSetKind(c, Style(style).features())
+	if kind == JsonValueNull|JsonValueNumber {
+		kind &= k
+		if kind == JsonValueNull|JsonValueNumber {
+			return scannerOfCapitalN(style)
+		}
+	}
+
+	if kind != 0 {
+		return k.CanScan(kind, style)
+	}
+
+	return nil
+}
+
+// Get scanner of value starts with 'N', which is None or NaN, see kindOfCapitalN.
+func scannerOfCapitalN(style int) JsonTokenScanner {
+	null := GetScannerOf(JsonValueNull, style)
+	number := GetScannerOf(JsonValueNumber, style)
+	return func(s []byte, i int) (int, int, error) {
+		if kindOfCapitalN(s, i) == JsonValueNumber {
+			return number(s, i)
+		}
 
-	default:
-		return nil
+		return null(s, i)
 	}
 }
@@ -1,6 +1,7 @@
 package findjson
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -37,15 +38,15 @@ func TestJsonValueKindJSS(t *testing.T) {
 	}
 
 	j := JsonValueArray
-	if j.CanScan(JsonValueArray, JavaScriptStyle) == nil {
+	if j.CanScan(JsonValueArray, JavaScriptLiteralStyle) == nil {
 		t.Errorf("CanScan(JsonValueArray) returns nil")
 	}
 
-	if f := j.CanScan(JsonValueAll, JavaScriptStyle); f != nil {
+	if f := j.CanScan(JsonValueAll, JavaScriptLiteralStyle); f != nil {
 		t.Errorf("CanScan(JsonValueAll) returns %v", f)
 	}
 
-	if f := j.CanScan(JsonValueObject, JavaScriptStyle); f != nil {
+	if f := j.CanScan(JsonValueObject, JavaScriptLiteralStyle); f != nil {
 		t.Errorf("CanScan(JsonValueObject) returns %v", f)
 	}
 }
@@ -55,13 +56,55 @@ func TestGetUnknownStyle(t *testing.T) {
 		t.Errorf("GetScannerOf(JsonValueArray, NormativeStyle) returns %v", f)
 	}
 
-	if f := GetScannerOf(JsonValueArray, JavaScriptStyle); f == nil {
-		t.Errorf("GetScannerOf(JsonValueArray, JavaScriptStyle) returns %v", f)
+	if f := GetScannerOf(JsonValueArray, JavaScriptLiteralStyle); f == nil {
+		t.Errorf("GetScannerOf(JsonValueArray, JavaScriptLiteralStyle) returns %v", f)
 	}
 
 	if f := GetScannerOf(JsonValueArray, -1); f != nil {
 		t.Errorf("GetScannerOf(JsonValueArray, -1) returns %v", f)
 	}
+
+	if f := GetScannerOf(JsonValueArray, StyleTrailingComma); f == nil {
+		t.Errorf("GetScannerOf(JsonValueArray, StyleTrailingComma) returns %v", f)
+	}
+
+	if f := GetScannerOf(JsonValueAll, StyleTrailingComma); f != nil {
+		t.Errorf("GetScannerOf(JsonValueAll, StyleTrailingComma) returns %v", f)
+	}
+}
+
+func TestGetScannerOfSingleQuote(t *testing.T) {
+	if f := JsonValueString.GetScanner('\'', NormativeStyle); f != nil {
+		t.Errorf("GetScanner('\\'', NormativeStyle) returns %v", f)
+	}
+
+	if f := JsonValueString.GetScanner('\'', JavaScriptLiteralStyle); f == nil {
+		t.Errorf("GetScanner('\\'', JavaScriptLiteralStyle) returns %v", f)
+	}
+}
+
+func TestGetScannerOfCapitalN(t *testing.T) {
+	// None and NaN both start with 'N'
+	style := PythonStyle | StyleSpecialNumbers
+	f := JsonValueAll.GetScanner('N', style)
+	if f == nil {
+		t.Fatalf("GetScanner('N', %s) returns nil", Style(style))
+	}
+
+	for _, v := range []string{"None", "NaN"} {
+		if _, end, err := f([]byte(v), 0); err != nil || end != len(v) {
+			t.Errorf("scanner of 'N' returns %d, %v on %s", end, err, v)
+		}
+	}
+
+	f = JsonValueNull.GetScanner('N', style)
+	if _, _, err := f([]byte("NaN"), 0); err == nil {
+		t.Errorf("scanner of 'N' for null accepts NaN")
+	}
+
+	if f := JsonValueBoolean.GetScanner('N', style); f != nil {
+		t.Errorf("GetScanner('N', %s) for boolean returns %v", Style(style), f)
+	}
 }
 
 func TestJsonValueKindString(t *testing.T) {
@@ -93,3 +136,18 @@ func TestJsonValueKindString(t *testing.T) {
 		t.Errorf("JsonValueAll.String() returns '%s'", v)
 	}
 }
+
+func TestFirstSetChars(t *testing.T) {
+	for style := Style(0); style <= styleAllFeatures; style++ {
+		for kind := JsonValueNull; kind <= JsonValueKind(0x3f); kind++ {
+			chars := firstSetChars(kind, style)
+			for c := 0; c < 256; c++ {
+				inChars := strings.IndexByte(chars, byte(c)) >= 0
+				inFirstSet := kind&firstSetKind(byte(c), style) != 0
+				if inChars != inFirstSet {
+					t.Fatalf("firstSetChars(%s, %s) = %q, char %q", kind, style, chars, c)
+				}
+			}
+		}
+	}
+}
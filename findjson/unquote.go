@@ -0,0 +1,160 @@
+package findjson
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Decode JSON string s[start:end], the span returned by string scanner, quotes included.
+//
+// Escape sequences are decoded with the same rules as the scanner, UTF-16 surrogate pairs
+// in \uXXXX escapes are combined into one rune, and a lone surrogate is replaced by
+// U+FFFD, as encoding/json does. Single-quoted strings and \' escape of StyleSingleQuotes
+// are also decoded.
+func UnquoteString(s []byte, start int, end int) ([]byte, error) {
+	var dst []byte
+	if start >= 0 && start < end && end <= len(s) {
+		// span is checked by AppendUnquoteString, reserve only for valid ones
+		dst = make([]byte, 0, end-start)
+	}
+
+	return AppendUnquoteString(dst, s, start, end)
+}
+
+// Decode JSON string s[start:end] and append the result to dst, like UnquoteString.
+// On error, dst is returned with the content decoded before the error.
+func AppendUnquoteString(dst []byte, s []byte, start int, end int) ([]byte, error) {
+	if start < 0 || end > len(s) || start >= end {
+		err := NewJsonError(start, "invalid string span [%d, %d)", start, end)
+		return dst, err
+	}
+
+	quote := s[start]
+	if quote != jsonQuote && quote != jsonSingleQuote {
+		v := bufferFindSample(s, start, 1)
+		err := NewJsonError(start, "expect quote '\"', got '%s'", v)
+		return dst, err
+	}
+
+	last := end - 1
+	if last <= start || s[last] != quote {
+		v := bufferFindSample(s[:end], end, 1)
+		err := NewJsonError(end, "expect quote '%c', got '%s'", quote, v)
+		return dst, err
+	}
+
+	// content without quotes, the trailing quote is left as EOF of samples.
+	b := s[:last]
+	j := start + 1
+	for j < last {
+		k := j
+		for k < last && b[k] != jsonBackslash && b[k] != quote {
+			k++
+		}
+
+		dst = append(dst, b[j:k]...)
+		j = k
+		if j >= last {
+			break
+		}
+
+		if b[j] == quote {
+			err := NewJsonError(j, "unexpected quote '%c' in string", quote)
+			return dst, err
+		}
+
+		// backslash
+		j++
+		if j >= last {
+			// the closing quote is escaped
+			v := bufferFindSample(s[:end], end, 1)
+			err := NewJsonError(end, "expect quote '%c', got '%s'", quote, v)
+			return dst, err
+		}
+
+		c := b[j]
+		if isEscapeChar(c) || c == jsonSingleQuote {
+			dst = append(dst, unescapeChar(c))
+			j++
+			continue
+
+		} else if c != jsonUnicode {
+			v := bufferFindSample(b, j, 1)
+			err := NewJsonError(j, "expect escape char, got '%s'", v)
+			return dst, err
+		}
+
+		r, err := decodeHex4(b, j+1)
+		if err != nil {
+			return dst, err
+		}
+
+		j += 5
+		if utf16.IsSurrogate(r) {
+			// try to combine with following low surrogate, e.g. "\uD83D\uDE00" for U+1F600.
+			if j+1 < last && b[j] == jsonBackslash && b[j+1] == jsonUnicode {
+				r2, err := decodeHex4(b, j+2)
+				if err != nil {
+					return dst, err
+				}
+
+				if p := utf16.DecodeRune(r, r2); p != utf8.RuneError {
+					r = p
+					j += 6
+				} else {
+					r = utf8.RuneError
+				}
+
+			} else {
+				r = utf8.RuneError
+			}
+		}
+
+		var buf [utf8.UTFMax]byte
+		n := utf8.EncodeRune(buf[:], r)
+		dst = append(dst, buf[:n]...)
+	}
+
+	return dst, nil
+}
+
+// Decode 4 hex digits at s[i:i+4] as an UTF-16 code unit.
+func decodeHex4(s []byte, i int) (rune, error) {
+	var r rune
+	l := len(s)
+
+	for k := 0; k < 4; k++ {
+		if i+k >= l || !isHexDigit(s[i+k]) {
+			v := bufferFindSample(s, i, 4)
+			err := NewJsonError(i, "expect 4 hex digits, got '%s'", v)
+			return 0, err
+		}
+
+		r = r<<4 | rune(hexDigitValue(s[i+k]))
+	}
+
+	return r, nil
+}
+
+// Get the byte represented by escape char c, which is after backslash.
+func unescapeChar(c byte) byte {
+	switch c {
+	case 'b':
+		return '\b'
+
+	case 'f':
+		return '\f'
+
+	case 'n':
+		return '\n'
+
+	case 'r':
+		return '\r'
+
+	case 't':
+		return '\t'
+	}
+
+	// '"', '\\', '/' and '\''
+	return c
+}
@@ -0,0 +1,98 @@
+package findjson
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnquoteStringSuccess(t *testing.T) {
+	caseList := []string{
+		`""`,
+		`"abc"`,
+		`"abc\ndef\fghi\tjkl\rmno\"pqr\\stu\/vwx\by"`,
+		`"abc䶮bc"`,
+		`"été"`,
+		`"😀 smile"`,
+		`"lone \ud83d surrogate"`,
+		`"lone \ude00 low surrogate"`,
+		`"high \ud83dA then letter"`,
+		`"utf-8 直接 text"`,
+	}
+
+	for _, item := range caseList {
+		s := []byte(item)
+		got, err := UnquoteString(s, 0, len(s))
+		if err != nil {
+			t.Errorf("UnquoteString(%s) returns error: %s", item, err)
+			continue
+		}
+
+		var exp string
+		if err := json.Unmarshal(s, &exp); err != nil {
+			t.Fatalf("json.Unmarshal(%s) returns error: %s", item, err)
+		}
+
+		if string(got) != exp {
+			t.Errorf("UnquoteString(%s) returns '%s', expected '%s'", item, got, exp)
+		}
+	}
+}
+
+func TestUnquoteStringInBuffer(t *testing.T) {
+	//           0         1         2
+	//           0123456789012345678901234
+	s := []byte(`{"key": "value\tdata"}`)
+	//                   |<---------->|
+	start, end, err := scanJsonString(s, 8)
+	if err != nil {
+		t.Fatalf("scanJsonString(s, 8) returns %d, %d, %s", start, end, err)
+	}
+
+	got, err := AppendUnquoteString([]byte("prefix:"), s, start, end)
+	if err != nil {
+		t.Fatalf("AppendUnquoteString(s, %d, %d) returns error: %s", start, end, err)
+	}
+
+	if string(got) != "prefix:value\tdata" {
+		t.Errorf("AppendUnquoteString(s, %d, %d) returns '%s'", start, end, got)
+	}
+}
+
+func TestUnquoteStringFailure(t *testing.T) {
+	type failureCase struct {
+		s   string
+		msg string
+	}
+
+	caseList := []failureCase{
+		{`abc"`, "JSON error at 0: expect quote '\"', got 'a'"},
+		{`"abc`, "JSON error at 4: expect quote '\"', got 'EOF'"},
+		{`"`, "JSON error at 1: expect quote '\"', got 'EOF'"},
+		{`"a"b"`, "JSON error at 2: unexpected quote '\"' in string"},
+		{`"abc\"`, "JSON error at 6: expect quote '\"', got 'EOF'"},
+		{`"abc\x"`, "JSON error at 5: expect escape char, got 'x'"},
+		{`"abc\u12"`, "JSON error at 6: expect 4 hex digits, got '12'"},
+		{`"abc\u12zz"`, "JSON error at 6: expect 4 hex digits, got '12zz'"},
+		{`"\ud83d\u12"`, "JSON error at 9: expect 4 hex digits, got '12'"},
+	}
+
+	for _, c := range caseList {
+		s := []byte(c.s)
+		got, err := UnquoteString(s, 0, len(s))
+		if err == nil {
+			t.Errorf("UnquoteString(%s) returns '%s', nil", c.s, got)
+			continue
+		}
+
+		if err.Error() != c.msg {
+			t.Errorf("UnquoteString(%s) returns error: %s", c.s, err)
+		}
+	}
+
+	spans := [][2]int{{3, 10}, {3, 1}, {-1, 2}, {2, 2}}
+	for _, span := range spans {
+		if _, err := UnquoteString([]byte(`"abc"`), span[0], span[1]); err == nil {
+			t.Errorf("UnquoteString with invalid span [%d, %d) returns nil error", span[0], span[1])
+		}
+	}
+}